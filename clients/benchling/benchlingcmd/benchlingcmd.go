@@ -79,7 +79,7 @@ func (c *Command) Crawl(ctx context.Context, _ CrawlFlags, entities ...string) e
 			return err
 		})
 	}
-	errs.Append(entityGroup.Wait())
+	errs.Append(apicrawlcmd.StoppedByCircuitBreaker(ctx, entityGroup.Wait()))
 	close(ch)
 	errs.Append(crawlGroup.Wait())
 	errs.Append(c.state.Checkpoint.Compact(ctx, ""))
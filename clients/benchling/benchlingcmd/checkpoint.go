@@ -6,10 +6,10 @@ package benchlingcmd
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"cloudeng.io/file/checkpoint"
+	"cloudeng.io/webapi/operations"
 )
 
 type Checkpoint struct {
@@ -22,15 +22,9 @@ var dayZero = time.Time{}.Format(time.RFC3339)
 
 func loadCheckpoint(ctx context.Context, op checkpoint.Operation) (Checkpoint, error) {
 	var cp Checkpoint
-	buf, err := op.Latest(ctx)
-	if err != nil {
+	if _, err := operations.LoadCheckpoint(ctx, op, &cp); err != nil {
 		return cp, err
 	}
-	if len(buf) > 0 {
-		if err := json.Unmarshal(buf, &cp); err != nil {
-			return cp, err
-		}
-	}
 	if len(cp.UsersDate) == 0 {
 		cp.UsersDate = dayZero
 	}
@@ -41,10 +35,5 @@ func loadCheckpoint(ctx context.Context, op checkpoint.Operation) (Checkpoint, e
 }
 
 func saveCheckpoint(ctx context.Context, op checkpoint.Operation, cp Checkpoint) error {
-	buf, err := json.Marshal(cp)
-	if err != nil {
-		return err
-	}
-	_, err = op.Checkpoint(ctx, "", buf)
-	return err
+	return operations.SaveCheckpoint(ctx, op, cp)
 }
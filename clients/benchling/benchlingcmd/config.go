@@ -63,6 +63,13 @@ func OptionsForEndpoint(cfg apicrawlcmd.Crawl[Service]) ([]operations.Option, er
 	if len(cfg.KeyID) > 0 {
 		opts = append(opts, operations.WithAuth(benchling.APIToken{TokenID: cfg.KeyID}))
 	}
+	client, err := cfg.HTTPClient.NewHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, operations.WithHTTPClient(client))
+	opts = append(opts, cfg.Circuit.Options()...)
+	opts = append(opts, cfg.Adaptive.Options()...)
 	rateCfg := cfg.RateControl
 	rcopts := []ratecontrol.Option{}
 	if rateCfg.Rate.BytesPerTick > 0 {
@@ -78,7 +85,9 @@ func OptionsForEndpoint(cfg apicrawlcmd.Crawl[Service]) ([]operations.Option, er
 					return benchling.NewBackoff(rateCfg.ExponentialBackoff.InitialDelay, rateCfg.ExponentialBackoff.Steps)
 				}))
 	}
-	rc := ratecontrol.New(rcopts...)
-	opts = append(opts, operations.WithRateController(rc, cfg.RateControl.ExponentialBackoff.StatusCodes...))
+	newController := func() *ratecontrol.Controller {
+		return ratecontrol.New(rcopts...)
+	}
+	opts = append(opts, operations.WithSharedRateControl(nil, cfg.KeyID, newController, cfg.RateControl.ExponentialBackoff.StatusCodes...))
 	return opts, nil
 }
@@ -6,6 +6,7 @@ package benchling
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -66,20 +67,48 @@ type ScanPayload[T any] struct {
 type paginator[T any] struct {
 	serviceURL string
 	params     any
+	nextToken  *string
+	done       bool
 }
 
 func (pg *paginator[T]) Next(_ context.Context, t T, r *http.Response) (req *http.Request, done bool, err error) {
 	if r == nil {
+		if pg.done {
+			done = true
+			return
+		}
 		req, err = createRequest(pg.serviceURL, pg.params)
 		return
 	}
 	nt := getNextToken(t)
 	done = nt == nil || len(*nt) == 0
+	pg.nextToken, pg.done = nt, done
 	setNextToken(pg.params, nt)
 	req, err = createRequest(pg.serviceURL, pg.params)
 	return
 }
 
+type paginatorState struct {
+	NextToken *string `json:"next_token,omitempty"`
+	Done      bool    `json:"done"`
+}
+
+// MarshalState implements operations.ResumablePaginator.
+func (pg *paginator[T]) MarshalState() ([]byte, error) {
+	return json.Marshal(paginatorState{NextToken: pg.nextToken, Done: pg.done})
+}
+
+// UnmarshalState implements operations.ResumablePaginator.
+func (pg *paginator[T]) UnmarshalState(buf []byte) error {
+	var state paginatorState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return err
+	}
+	pg.nextToken, pg.done = state.NextToken, state.Done
+	setNextToken(pg.params, state.NextToken)
+	return nil
+}
+
 func newPaginator[T Scanners](_ context.Context, serviceURL string, params any) operations.Paginator[T] {
 	pg := &paginator[T]{
 		serviceURL: serviceURL,
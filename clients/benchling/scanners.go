@@ -64,6 +64,13 @@ func NewScanner[ScannerT Scanners, ParamsT Params](ctx context.Context, serviceU
 	return operations.NewScanner(pg, opts...)
 }
 
+// NewScannerFrom is like NewScanner but resumes a scan from the state
+// returned by the Checkpoint method of a Scanner created by NewScanner.
+func NewScannerFrom[ScannerT Scanners, ParamsT Params](ctx context.Context, serviceURL string, params ParamsT, state []byte, opts ...operations.Option) (*operations.Scanner[ScannerT], error) {
+	pg := newPaginator[ScannerT](ctx, serviceURL, params)
+	return operations.NewScannerFrom(pg, state, opts...)
+}
+
 type Objects interface {
 	benchlingsdk.Entry | benchlingsdk.User | benchlingsdk.Folder | benchlingsdk.Project | Document
 }
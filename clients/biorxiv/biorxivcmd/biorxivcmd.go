@@ -98,7 +98,7 @@ func (c *Command) Crawl(ctx context.Context, flags CrawlFlags) error {
 	}
 	close(ch)
 	var errs errors.M
-	errs.Append(sc.Err())
+	errs.Append(apicrawlcmd.StoppedByCircuitBreaker(ctx, sc.Err()))
 	err = <-errCh
 	if err != nil && strings.Contains(err.Error(), "no articles found for") {
 		err = nil
@@ -16,6 +16,7 @@ type Service struct {
 	ServiceURL string           `yaml:"service_url" cmd:"rxiv service URL, eg. https://api.biorxiv.org/pubs/biorxiv for biorxiv"`
 	StartDate  cmdyaml.FlexTime `yaml:"start_date" cmd:"start date for crawl, eg. 2020-01-01"`
 	EndDate    cmdyaml.FlexTime `yaml:"end_date" cmd:"end date for crawl, eg. 2020-12-01"`
+	Prefetch   int              `yaml:"prefetch" cmd:"number of pages of preprints to fetch concurrently, defaults to 1"`
 	// Note, that the Cursor value is generally obtained a from a checkpoint file.
 }
 
@@ -23,6 +24,13 @@ type Service struct {
 // apicrawlcmd configuration.
 func OptionsForEndpoint(cfg apicrawlcmd.Crawl[Service]) ([]operations.Option, error) {
 	opts := []operations.Option{}
+	client, err := cfg.HTTPClient.NewHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, operations.WithHTTPClient(client))
+	opts = append(opts, cfg.Circuit.Options()...)
+	opts = append(opts, cfg.Adaptive.Options()...)
 	rateCfg := cfg.RateControl
 	rcopts := []ratecontrol.Option{}
 	if rateCfg.Rate.BytesPerTick > 0 {
@@ -35,7 +43,12 @@ func OptionsForEndpoint(cfg apicrawlcmd.Crawl[Service]) ([]operations.Option, er
 		rcopts = append(rcopts,
 			ratecontrol.WithExponentialBackoff(rateCfg.ExponentialBackoff.InitialDelay, rateCfg.ExponentialBackoff.Steps))
 	}
-	rc := ratecontrol.New(rcopts...)
-	opts = append(opts, operations.WithRateController(rc, cfg.RateControl.ExponentialBackoff.StatusCodes...))
+	newController := func() *ratecontrol.Controller {
+		return ratecontrol.New(rcopts...)
+	}
+	opts = append(opts, operations.WithSharedRateControl(nil, cfg.KeyID, newController, cfg.RateControl.ExponentialBackoff.StatusCodes...))
+	if cfg.Service.Prefetch > 1 {
+		opts = append(opts, operations.WithPrefetch(cfg.Service.Prefetch))
+	}
 	return opts, nil
 }
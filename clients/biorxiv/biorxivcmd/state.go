@@ -6,10 +6,10 @@ package biorxivcmd
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"cloudeng.io/file/checkpoint"
+	"cloudeng.io/webapi/operations"
 )
 
 type crawlState struct {
@@ -20,12 +20,8 @@ type crawlState struct {
 }
 
 func loadState(ctx context.Context, op checkpoint.Operation) (crawlState, error) {
-	buf, err := op.Latest(ctx)
-	if err != nil || buf == nil {
-		return crawlState{}, err
-	}
 	var cs crawlState
-	if err := json.Unmarshal(buf, &cs); err != nil {
+	if _, err := operations.LoadCheckpoint(ctx, op, &cs); err != nil {
 		return crawlState{}, err
 	}
 	return cs, nil
@@ -60,10 +56,5 @@ func (cs *crawlState) update(counter, total int64) {
 }
 
 func (cs *crawlState) save(ctx context.Context, op checkpoint.Operation) error {
-	buf, err := json.MarshalIndent(cs, "", "  ")
-	if err != nil {
-		return err
-	}
-	_, err = op.Checkpoint(ctx, "", buf)
-	return err
+	return operations.SaveCheckpoint(ctx, op, cs)
 }
@@ -6,6 +6,7 @@ package biorxiv
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -20,10 +21,37 @@ type paginator struct {
 	serviceURL string
 	from, to   time.Time
 	cursor     int64
+	done       bool
+}
+
+type paginatorState struct {
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Cursor int64     `json:"cursor"`
+	Done   bool      `json:"done"`
+}
+
+// MarshalState implements operations.ResumablePaginator.
+func (pg *paginator) MarshalState() ([]byte, error) {
+	return json.Marshal(paginatorState{From: pg.from, To: pg.to, Cursor: pg.cursor, Done: pg.done})
+}
+
+// UnmarshalState implements operations.ResumablePaginator.
+func (pg *paginator) UnmarshalState(buf []byte) error {
+	var state paginatorState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return err
+	}
+	pg.from, pg.to, pg.cursor, pg.done = state.From, state.To, state.Cursor, state.Done
+	return nil
 }
 
 func (pg *paginator) Next(_ context.Context, t Response, r *http.Response) (req *http.Request, done bool, err error) {
 	if r == nil {
+		if pg.done {
+			done = true
+			return
+		}
 		var u string
 		u, err = url.JoinPath(pg.serviceURL, pg.from.Format("2006-01-02"), pg.to.Format("2006-01-02"), strconv.FormatInt(pg.cursor, 10))
 		if err != nil {
@@ -33,7 +61,7 @@ func (pg *paginator) Next(_ context.Context, t Response, r *http.Response) (req
 		return
 	}
 	if len(t.Messages) == 0 {
-		done = true
+		pg.done, done = true, true
 		return
 	}
 	msg := t.Messages[0]
@@ -49,9 +77,10 @@ func (pg *paginator) Next(_ context.Context, t Response, r *http.Response) (req
 		return
 	}
 	if cursor+msg.Count >= total {
-		done = true
+		pg.done, done = true, true
 		return
 	}
+	pg.cursor = cursor + msg.Count
 	u, err := url.JoinPath(pg.serviceURL, pg.from.Format("2006-01-02"), pg.to.Format("2006-01-02"),
 		strconv.FormatInt(cursor+msg.Count, 10))
 	if err != nil {
@@ -61,6 +90,41 @@ func (pg *paginator) Next(_ context.Context, t Response, r *http.Response) (req
 	return
 }
 
+// Upcoming implements operations.PrefetchPaginator. The requests for all of
+// the remaining pages are determined from the cursor, count and total
+// returned in the first response.
+func (pg *paginator) Upcoming(_ context.Context, t Response, _ *http.Response) ([]*http.Request, error) {
+	if len(t.Messages) == 0 {
+		return nil, nil
+	}
+	msg := t.Messages[0]
+	if msg.Count <= 0 {
+		return nil, nil
+	}
+	cursor, err := internal.AsInt64(msg.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected cursor: %v: %v", msg.Cursor, err)
+	}
+	total, err := internal.AsInt64(msg.Total)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected total: %v: %v", msg.Total, err)
+	}
+	var reqs []*http.Request
+	for next := cursor + msg.Count; next < total; next += msg.Count {
+		u, err := url.JoinPath(pg.serviceURL, pg.from.Format("2006-01-02"), pg.to.Format("2006-01-02"),
+			strconv.FormatInt(next, 10))
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
 // NewScanner returns an instance of operations.Scanner for scanning
 // biorxiv or medrxiv via api.biorxiv.org. The from, to and cursor
 // values corresponding to URL path components as documented at:
@@ -74,3 +138,10 @@ func NewScanner(serviceURL string, from, to time.Time, cursor int64, opts ...ope
 	}
 	return operations.NewScanner[Response](pg, opts...)
 }
+
+// NewScannerFrom returns an instance of operations.Scanner that resumes
+// a scan from the state returned by the Checkpoint method of a Scanner
+// created by NewScanner.
+func NewScannerFrom(serviceURL string, state []byte, opts ...operations.Option) (*operations.Scanner[Response], error) {
+	return operations.NewScannerFrom[Response](&paginator{serviceURL: serviceURL}, state, opts...)
+}
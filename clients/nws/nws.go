@@ -8,6 +8,7 @@ package nws
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -47,6 +48,10 @@ func (o OpaqueCloudCoverage) String() string {
 
 const (
 	APIHost = "https://api.weather.gov"
+
+	// DefaultUserAgent is the User-Agent sent with requests unless
+	// overridden using WithUserAgent.
+	DefaultUserAgent = "(cloudeng.io/webapi/clients/nws)"
 )
 
 type gridPointResponse struct {
@@ -69,9 +74,12 @@ type forecastResponse struct {
 
 // API represents a client for the National Weather Service API.
 type API struct {
-	host          string
-	pointsCache   *gridPointsCache
-	forecastCache *forecastCache
+	host            string
+	userAgent       string
+	pointsCache     *gridPointsCache
+	forecastCache   *forecastCache
+	rateControllers *operations.RateControllers
+	httpClient      *http.Client
 }
 
 type Option func(o *options)
@@ -94,9 +102,46 @@ func WithForecastExpiration(d time.Duration) Option {
 	}
 }
 
+// WithRateControllers sets the registry from which the rate controller
+// shared by all requests made by the API is obtained, the default is
+// operations.DefaultRateControllers. The controller is keyed by the API's
+// host and an empty key ID and may be configured in advance using the
+// registry's Set method. Note that a rate controller specified via
+// operations.WithRateController when calling LookupGridPoints or
+// GetForecasts takes precedence over the shared controller.
+func WithRateControllers(r *operations.RateControllers) Option {
+	return func(o *options) {
+		o.rateControllers = r
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with all requests. The
+// NWS API requires a User-Agent that identifies the application and
+// includes contact information, eg. "(myweatherapp.com, contact@myweatherapp.com)",
+// the default is DefaultUserAgent.
+func WithUserAgent(ua string) Option {
+	return func(o *options) {
+		o.userAgent = ua
+	}
+}
+
+// WithHTTPClient sets the http.Client used for all requests made by the
+// API, the default is http.DefaultClient. The client may be created from
+// an apicrawlcmd.HTTPClient configuration using its NewHTTPClient method.
+// Note that a client specified via operations.WithHTTPClient when calling
+// LookupGridPoints or GetForecasts takes precedence.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
 type options struct {
 	gridpointExpiration time.Duration
 	forecastExpiration  time.Duration
+	rateControllers     *operations.RateControllers
+	userAgent           string
+	httpClient          *http.Client
 }
 
 // NewAPI creates a new instance of the National Weather Service API client.
@@ -106,10 +151,19 @@ func NewAPI(opts ...Option) *API {
 	for _, fn := range opts {
 		fn(&o)
 	}
+	if len(o.userAgent) == 0 {
+		o.userAgent = DefaultUserAgent
+	}
+	if o.rateControllers == nil {
+		o.rateControllers = operations.DefaultRateControllers
+	}
 	api := &API{
-		host:          APIHost,
-		pointsCache:   newGridPointsCache(o.gridpointExpiration),
-		forecastCache: newForecastCache(o.forecastExpiration),
+		host:            APIHost,
+		userAgent:       o.userAgent,
+		pointsCache:     newGridPointsCache(o.gridpointExpiration),
+		forecastCache:   newForecastCache(o.forecastExpiration),
+		rateControllers: o.rateControllers,
+		httpClient:      o.httpClient,
 	}
 
 	return api
@@ -118,6 +172,20 @@ func (a *API) SetHost(host string) {
 	a.host = host
 }
 
+// endpointOptions returns the options for an Endpoint, the shared rate
+// controller and http client are specified first so that they may be
+// overridden by opts.
+func (a *API) endpointOptions(opts []operations.Option) []operations.Option {
+	defaults := []operations.Option{
+		operations.WithSharedRateControl(a.rateControllers, "", nil),
+		operations.WithMiddleware(operations.UserAgent(a.userAgent)),
+	}
+	if a.httpClient != nil {
+		defaults = append(defaults, operations.WithHTTPClient(a.httpClient))
+	}
+	return append(defaults, opts...)
+}
+
 // GridPoints represents the grid points for a specific lat/long.
 type GridPoints struct {
 	ID    string
@@ -143,7 +211,7 @@ func (a *API) LookupGridPoints(ctx context.Context, lat, long float64, opts ...o
 		return GridPoints{}, fmt.Errorf("failed to parse URL: %v: %w", ustr, err)
 	}
 
-	ep := operations.NewEndpoint[gridPointResponse](opts...)
+	ep := operations.NewEndpoint[gridPointResponse](a.endpointOptions(opts)...)
 	gpr, _, _, err := ep.Get(ctx, u.String())
 	if err != nil {
 		return GridPoints{}, fmt.Errorf("%v: grid point lookup failed: %w", u.String(), err)
@@ -189,7 +257,7 @@ func (a *API) GetForecasts(ctx context.Context, gp GridPoints, opts ...operation
 	if err != nil {
 		return Forecast{}, fmt.Errorf("failed to parse URL: %v: %w", ustr, err)
 	}
-	ep := operations.NewEndpoint[forecastResponse](opts...)
+	ep := operations.NewEndpoint[forecastResponse](a.endpointOptions(opts)...)
 	frc, _, _, err := ep.Get(ctx, u.String())
 	if err != nil {
 		return Forecast{}, fmt.Errorf("%v: forecast download failed: %w", u.String(), err)
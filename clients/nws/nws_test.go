@@ -6,6 +6,7 @@ package nws_test
 
 import (
 	"context"
+	"net/http"
 	"slices"
 	"testing"
 	"time"
@@ -179,3 +180,29 @@ func TestPeriodLookup(t *testing.T) {
 		t.Errorf("got %v, want %v", got, want)
 	}
 }
+
+type countingTransport struct {
+	requests int
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHTTPClient(t *testing.T) {
+	ctx := context.Background()
+	srv := nwstestutil.NewMockServer()
+	defer srv.Close()
+	url := srv.Run()
+
+	transport := &countingTransport{}
+	api := nws.NewAPI(nws.WithHTTPClient(&http.Client{Transport: transport}))
+	api.SetHost(url)
+	if _, err := api.LookupGridPoints(ctx, 39.7456, -97.0892); err != nil {
+		t.Fatalf("failed to get grid points: %v", err)
+	}
+	if got, want := transport.requests, 1; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -22,13 +22,18 @@ type APIToken struct {
 	token        papersappsdk.Token
 	issued       time.Time
 	refreshURL   string
+	opts         []operations.Option
 	mu           sync.Mutex
 }
 
-func NewAPIToken(refreshKeyID, refreshURL string) *APIToken {
+// NewAPIToken returns a new APIToken that uses the API key identified by
+// refreshKeyID to obtain access tokens from refreshURL. The supplied options
+// are used for the endpoint that makes the refresh requests.
+func NewAPIToken(refreshKeyID, refreshURL string, opts ...operations.Option) *APIToken {
 	return &APIToken{
 		refreshURL:   refreshURL,
 		refreshKeyID: refreshKeyID,
+		opts:         opts,
 	}
 }
 
@@ -41,6 +46,23 @@ func (pbt *APIToken) WithAuthorization(ctx context.Context, req *http.Request) e
 	return nil
 }
 
+// Invalidate implements operations.Invalidator. It discards the current
+// access token, if the request was rejected as unauthorized, so that a new
+// one is obtained when the request is re-authorized.
+func (pbt *APIToken) Invalidate(_ context.Context, resp *http.Response) bool {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	pbt.mu.Lock()
+	defer pbt.mu.Unlock()
+	if resp.Request != nil && resp.Request.Header.Get("Authorization") != "Bearer "+pbt.token.Token {
+		// The request used a token that has since been replaced.
+		return true
+	}
+	pbt.issued = time.Time{}
+	return true
+}
+
 func (pbt *APIToken) Refresh(ctx context.Context) (papersappsdk.Token, error) {
 	// only allow one outstanding refresh at a time
 	pbt.mu.Lock()
@@ -52,7 +74,7 @@ func (pbt *APIToken) Refresh(ctx context.Context) (papersappsdk.Token, error) {
 	if !ok {
 		return papersappsdk.Token{}, apitokens.NewErrNotFound(pbt.refreshKeyID, pbt.refreshURL)
 	}
-	ep := operations.NewEndpoint[papersappsdk.Token]()
+	ep := operations.NewEndpoint[papersappsdk.Token](pbt.opts...)
 	req, err := http.NewRequest("GET", pbt.refreshURL+"?api_key="+string(refreshToken.Value()), nil)
 	if err != nil {
 		return papersappsdk.Token{}, err
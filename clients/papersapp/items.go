@@ -5,13 +5,11 @@
 package papersapp
 
 import (
-	"context"
-	"fmt"
-	"net/http"
 	"net/url"
 
 	"cloudeng.io/webapi/clients/papersapp/papersappsdk"
 	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/operations/paginators"
 )
 
 const (
@@ -31,29 +29,21 @@ type ItemPaginatorOptions struct {
 	Parameters  url.Values
 }
 
-type itemPaginator struct {
-	ItemPaginatorOptions
-	downloaded int
-}
-
-// Next implements operations.Paginator.
-func (pg *itemPaginator) Next(_ context.Context, items papersappsdk.Items, resp *http.Response) (req *http.Request, done bool, err error) {
-	// Pagination for uses a scroll_id parameter to request the next
-	// page, but relies on the client counting items until the total
-	// number of items is reached. Also note that the scroll_id will be
-	// repeated if the end of the list is reached but this requires an
-	// extra request to be made and hence counting items is preferable.
-	if resp != nil {
-		pg.downloaded += len(items.Items)
-		if int64(pg.downloaded) >= items.Total {
-			done = true
-		}
-		pg.Parameters.Set("scroll_id", items.ScrollID)
-	}
-	req, err = http.NewRequest("GET", fmt.Sprintf("%v?%v", pg.EndpointURL, pg.Parameters.Encode()), nil)
-	return
-}
-
+// NewItemPaginator returns a paginator for the items in a collection.
+// Pagination uses a scroll_id parameter to request the next page, but
+// relies on the client counting items until the total number of items
+// is reached. Note that the scroll_id will be repeated if the end of the
+// list is reached but this requires an extra request to be made and hence
+// counting items is preferable.
 func NewItemPaginator(opts ItemPaginatorOptions) operations.Paginator[papersappsdk.Items] {
-	return &itemPaginator{ItemPaginatorOptions: opts}
+	return paginators.NewScrollID(
+		paginators.Request{
+			URL:        opts.EndpointURL,
+			Parameters: opts.Parameters,
+		},
+		"scroll_id",
+		paginators.Func(func(items papersappsdk.Items) string { return items.ScrollID }),
+		paginators.Func(func(items papersappsdk.Items) int { return len(items.Items) }),
+		paginators.Func(func(items papersappsdk.Items) int64 { return items.Total }),
+	)
 }
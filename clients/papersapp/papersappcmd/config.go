@@ -17,14 +17,20 @@ type Service struct {
 }
 
 func OptionsForEndpoint(cfg apicrawlcmd.Crawl[Service]) ([]operations.Option, error) {
-	opts := []operations.Option{}
+	client, err := cfg.HTTPClient.NewHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	opts := []operations.Option{operations.WithHTTPClient(client)}
+	opts = append(opts, cfg.Circuit.Options()...)
+	opts = append(opts, cfg.Adaptive.Options()...)
 	if len(cfg.KeyID) > 0 {
-		opts = append(opts, operations.WithAuth(papersapp.NewAPIToken(cfg.KeyID, cfg.Service.RefreshTokenURL)))
+		opts = append(opts, operations.WithAuth(papersapp.NewAPIToken(cfg.KeyID, cfg.Service.RefreshTokenURL, operations.WithHTTPClient(client))))
 	}
-	rc, err := cfg.RateControl.NewRateController()
+	rc, err := cfg.SharedRateControl(nil)
 	if err != nil {
 		return nil, err
 	}
-	opts = append(opts, operations.WithRateController(rc, cfg.RateControl.ExponentialBackoff.StatusCodes...))
+	opts = append(opts, rc)
 	return opts, nil
 }
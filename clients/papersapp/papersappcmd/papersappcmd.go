@@ -61,7 +61,7 @@ func (c *Command) Crawl(ctx context.Context, _ *CrawlFlags) error {
 
 	collections, err := papersapp.ListCollections(ctx, c.state.Config.Service.ServiceURL, opts...)
 	if err != nil {
-		return err
+		return apicrawlcmd.StoppedByCircuitBreaker(ctx, err)
 	}
 
 	collectionsCache := stores.New(c.state.Store, c.state.Config.Cache.Concurrency)
@@ -95,7 +95,7 @@ func (c *Command) Crawl(ctx context.Context, _ *CrawlFlags) error {
 			opts:       opts,
 		}
 		if err := crawler.run(ctx); err != nil {
-			return err
+			return apicrawlcmd.StoppedByCircuitBreaker(ctx, err)
 		}
 	}
 	return nil
@@ -25,56 +25,72 @@ type fetcher struct {
 	ep  *operations.Endpoint[protocolsiosdk.ProtocolPayload]
 }
 
-func (f *fetcher) fetch(ctx context.Context, p protocolsiosdk.Protocol) (protocolsiosdk.ProtocolPayload, operations.Response) {
+// fetch fetches the specified protocol. Errors that are specific to
+// the protocol are recorded in the returned Response so that the
+// protocol is skipped, all others are returned so that the crawl
+// is stopped, without its checkpoint advancing past the current page,
+// and can be resumed later.
+func (f *fetcher) fetch(ctx context.Context, p protocolsiosdk.Protocol) (protocolsiosdk.ProtocolPayload, operations.Response, error) {
 	var response operations.Response
 	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%v", f.url, p.ID), nil)
 	if err != nil {
 		response.Error = content.Error(err)
-		return protocolsiosdk.ProtocolPayload{}, response
+		return protocolsiosdk.ProtocolPayload{}, response, nil
 	}
 	payload, body, enc, resp, err := f.ep.IssueRequest(ctx, req)
 	if err != nil {
+		if operations.ActionForError(err) != operations.Skip {
+			return protocolsiosdk.ProtocolPayload{}, response, err
+		}
 		response.Error = content.Error(err)
-		return protocolsiosdk.ProtocolPayload{}, response
+		return protocolsiosdk.ProtocolPayload{}, response, nil
 	}
 	response.Encoding = enc
 	response.When = time.Now().Truncate(0)
 	response.Bytes = body
 	response.FromHTTPResponse(resp)
-	return payload, response
+	return payload, response, nil
 }
 
-func (f *fetcher) Fetch(ctx context.Context, page protocolsiosdk.ListProtocolsV3, ch chan<- []content.Object[protocolsiosdk.ProtocolPayload, operations.Response]) error {
-	all := []content.Object[protocolsiosdk.ProtocolPayload, operations.Response]{}
-	for i, item := range page.Items {
-		crawled := content.Object[protocolsiosdk.ProtocolPayload, operations.Response]{
-			Type: ContentType,
-		}
-		var p protocolsiosdk.Protocol
-		var outdated bool
-		if err := json.Unmarshal(item, &p); err != nil {
-			crawled.Response.Error = content.Error(err)
-		} else {
-			ver, ok := f.VersionMap[p.ID]
-			outdated = !ok || ver < p.VersionID
-			if outdated {
-				crawled.Value, crawled.Response = f.fetch(ctx, p)
-			}
+func (f *fetcher) fetchItem(ctx context.Context, item json.RawMessage) (content.Object[protocolsiosdk.ProtocolPayload, operations.Response], error) {
+	crawled := content.Object[protocolsiosdk.ProtocolPayload, operations.Response]{
+		Type: ContentType,
+	}
+	var p protocolsiosdk.Protocol
+	if err := json.Unmarshal(item, &p); err != nil {
+		crawled.Response.Error = content.Error(err)
+		return crawled, nil
+	}
+	ver, ok := f.VersionMap[p.ID]
+	if outdated := !ok || ver < p.VersionID; outdated {
+		var err error
+		crawled.Value, crawled.Response, err = f.fetch(ctx, p)
+		if err != nil {
+			return crawled, err
 		}
-		// Always send an object, even if it's empty for a protocol
-		// that hasn't changed since we last crawled.
-		if i == len(page.Items)-1 {
+	}
+	return crawled, nil
+}
+
+func (f *fetcher) Fetch(ctx context.Context, page protocolsiosdk.ListProtocolsV3, ch chan<- []content.Object[protocolsiosdk.ProtocolPayload, operations.Response]) error {
+	// Always send an object, even if it's empty for a protocol
+	// that hasn't changed since we last crawled.
+	all, err := operations.FetchItems(ctx, f.Concurrency, page.Items, f.fetchItem)
+	if err != nil {
+		return err
+	}
+	for i := range all {
+		if i == len(all)-1 {
 			cp := Checkpoint{
 				CompletedPage: page.Pagination.CurrentPage,
 				CurrentPage:   page.Pagination.CurrentPage + 1,
 				TotalPages:    page.Pagination.TotalPages,
 			}
 			buf, _ := json.Marshal(cp)
-			crawled.Response.Checkpoint = buf
+			all[i].Response.Checkpoint = buf
 		}
-		crawled.Response.Current = page.Pagination.CurrentPage
-		crawled.Response.Total = page.Pagination.TotalPages
-		all = append(all, crawled)
+		all[i].Response.Current = page.Pagination.CurrentPage
+		all[i].Response.Total = page.Pagination.TotalPages
 	}
 	select {
 	case <-ctx.Done():
@@ -106,10 +122,13 @@ func (pbt PublicBearerToken) WithAuthorization(ctx context.Context, req *http.Re
 // The VersionMap contains the version ID of a previously downloaded instance
 // of that protocol, keyed by it's ID. The fetcher will only redownload a
 // protocol object if its version ID has channged. The VersionMap is typically
-// built by scanning all previously downloaded protocol objects.
+// built by scanning all previously downloaded protocol objects. Concurrency
+// specifies the number of protocols in each page that are fetched
+// concurrently.
 type FetcherOptions struct {
 	EndpointURL string
 	VersionMap  map[int64]int
+	Concurrency int
 }
 
 // NewFetcher returns an instance of operations.Fetcher for protocols.io
@@ -0,0 +1,78 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package protocolsio_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cloudeng.io/file/content"
+	"cloudeng.io/webapi/clients/protocolsio"
+	"cloudeng.io/webapi/clients/protocolsio/protocolsiosdk"
+	"cloudeng.io/webapi/operations"
+)
+
+func TestFetchStopsOnRetryLater(t *testing.T) {
+	ctx := context.Background()
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if strings.HasSuffix(r.URL.Path, "/2") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(protocolsiosdk.ProtocolPayload{})
+	}))
+	defer srv.Close()
+
+	cb := operations.NewCircuitBreakers(operations.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		CoolDown:         time.Hour,
+	})
+	fetcher, err := protocolsio.NewFetcher(
+		protocolsio.FetcherOptions{EndpointURL: srv.URL, Concurrency: 1},
+		operations.WithCircuitBreakers(cb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := protocolsiosdk.ListProtocolsV3{
+		Items: []json.RawMessage{
+			json.RawMessage(`{"id": 1}`),
+			json.RawMessage(`{"id": 2}`),
+		},
+		Pagination: protocolsiosdk.Pagination{CurrentPage: 3, TotalPages: 10},
+	}
+	ch := make(chan []content.Object[protocolsiosdk.ProtocolPayload, operations.Response], 2)
+
+	// A server error stops the crawl without sending the page, and hence
+	// its checkpoint.
+	err = fetcher.Fetch(ctx, page, ch)
+	if got, want := err, operations.ErrServerError; !errors.Is(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := len(ch), 0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The circuit is now open and the crawl is again stopped without
+	// advancing the checkpoint.
+	sent := requests
+	err = fetcher.Fetch(ctx, page, ch)
+	if got, want := err, operations.ErrCircuitOpen; !errors.Is(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := len(ch), 0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := requests, sent; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -9,7 +9,6 @@ package protocolsiocmd
 
 import (
 	"context"
-	"encoding/json"
 	"net/url"
 	"strconv"
 	"sync"
@@ -31,22 +30,9 @@ type Service struct {
 	OrderField     string `yaml:"order_field" cmd:"field used to order API responses, typically id"`
 	OrderDirection string `yaml:"order_direction" cmd:"order direction to apply to protocols.io API calls, typically asc"`
 	Incremental    bool   `yaml:"incremental" cmd:"if true, only download new or updated protocols"`
-}
-
-func latestCheckpoint(ctx context.Context, op checkpoint.Operation) (protocolsio.Checkpoint, error) {
-	if op == nil {
-		return protocolsio.Checkpoint{}, nil
-	}
-	buf, err := op.Latest(ctx)
-	if err != nil {
-		return protocolsio.Checkpoint{}, err
-	}
-	if len(buf) == 0 {
-		return protocolsio.Checkpoint{}, nil
-	}
-	var cp protocolsio.Checkpoint
-	err = json.Unmarshal(buf, &cp)
-	return cp, err
+	Prefetch       int    `yaml:"prefetch" cmd:"number of pages of protocols to fetch concurrently, defaults to 1"`
+	PageFetchers   int    `yaml:"page_fetchers" cmd:"number of pages for which protocol details are fetched concurrently, defaults to 1"`
+	ItemFetchers   int    `yaml:"item_fetchers" cmd:"number of protocols in each page whose details are fetched concurrently, defaults to 1"`
 }
 
 func createVersionMap(ctx context.Context, fs operations.FS, concurrency int, downloads string) (map[int64]int, error) {
@@ -82,8 +68,8 @@ func createVersionMap(ctx context.Context, fs operations.FS, concurrency int, do
 // that can be used to crawl/download protocols on protocols.io.
 func newProtocolCrawler(ctx context.Context, cfg apicrawlcmd.Crawl[Service], fs operations.FS, downloadsPath string, op checkpoint.Operation, fv *CrawlFlags) (*operations.Crawler[protocolsiosdk.ListProtocolsV3, protocolsiosdk.ProtocolPayload], error) {
 
-	cp, err := latestCheckpoint(ctx, op)
-	if err != nil {
+	var cp protocolsio.Checkpoint
+	if _, err := operations.LoadCheckpoint(ctx, op, &cp); err != nil {
 		return nil, err
 	}
 
@@ -108,6 +94,7 @@ func newProtocolCrawler(ctx context.Context, cfg apicrawlcmd.Crawl[Service], fs
 	// Fetcher options.
 	var fetcherOptions protocolsio.FetcherOptions
 	fetcherOptions.EndpointURL = protocolsiosdk.GetProtocolV4Endpoint
+	fetcherOptions.Concurrency = cfg.Service.ItemFetchers
 
 	if cfg.Service.Incremental {
 		vmap, err := createVersionMap(ctx, fs, cfg.Cache.Concurrency, downloadsPath)
@@ -134,10 +121,20 @@ func OptionsForEndpoint(cfg apicrawlcmd.Crawl[Service]) ([]operations.Option, er
 	if len(cfg.KeyID) > 0 {
 		opts = append(opts, operations.WithAuth(protocolsio.PublicBearerToken{KeyID: cfg.KeyID}))
 	}
-	rc, err := cfg.RateControl.NewRateController()
+	client, err := cfg.HTTPClient.NewHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, operations.WithHTTPClient(client))
+	opts = append(opts, cfg.Circuit.Options()...)
+	opts = append(opts, cfg.Adaptive.Options()...)
+	rc, err := cfg.SharedRateControl(nil)
 	if err != nil {
 		return nil, err
 	}
-	opts = append(opts, operations.WithRateController(rc, cfg.RateControl.ExponentialBackoff.StatusCodes...))
+	opts = append(opts, rc)
+	if cfg.Service.Prefetch > 1 {
+		opts = append(opts, operations.WithPrefetch(cfg.Service.Prefetch))
+	}
 	return opts, nil
 }
@@ -15,7 +15,6 @@ import (
 
 	"cloudeng.io/cmdutil/flags"
 	"cloudeng.io/errors"
-	"cloudeng.io/file/checkpoint"
 	"cloudeng.io/file/content"
 	"cloudeng.io/file/content/stores"
 	"cloudeng.io/file/filewalk"
@@ -76,9 +75,11 @@ func (c *Command) Crawl(ctx context.Context, fv *CrawlFlags) error {
 	var errs errors.M
 	err = operations.RunCrawl(ctx, crawler,
 		func(ctx context.Context, objects []content.Object[protocolsiosdk.ProtocolPayload, operations.Response]) error {
-			return handleCrawledObject(ctx, fv.Save, sharder, c.state.Store, downloadPath, c.state.Checkpoint, objects)
-		})
-	errs.Append(err)
+			return handleCrawledObject(ctx, fv.Save, sharder, c.state.Store, downloadPath, objects)
+		},
+		operations.WithFetchConcurrency(c.state.Config.Service.PageFetchers),
+		operations.WithCheckpoint(c.state.Checkpoint))
+	errs.Append(apicrawlcmd.StoppedByCircuitBreaker(ctx, err))
 	errs.Append(c.state.Checkpoint.Compact(ctx, ""))
 	return errs.Err()
 }
@@ -88,7 +89,6 @@ func handleCrawledObject(ctx context.Context,
 	sharder path.Sharder,
 	fs content.FS,
 	root string,
-	chk checkpoint.Operation,
 	objs []content.Object[protocolsiosdk.ProtocolPayload, operations.Response]) error {
 
 	store := stores.New(fs, 0)
@@ -98,11 +98,11 @@ func handleCrawledObject(ctx context.Context,
 		}
 		if obj.Value.Protocol.ID == 0 {
 			// Protocol is up-to-date on disk.
-			return nil
+			continue
 		}
 		ctxlog.Info(ctx, "protocols.io: protocol ID", "id", obj.Value.Protocol.ID)
 		if !save {
-			return nil
+			continue
 		}
 		// Save the protocol object to disk.
 		prefix, suffix := sharder.Assign(fmt.Sprintf("%v", obj.Value.Protocol.ID))
@@ -110,15 +110,6 @@ func handleCrawledObject(ctx context.Context,
 		if err := obj.Store(ctx, store, prefix, suffix, content.GOBObjectEncoding, content.GOBObjectEncoding); err != nil {
 			return err
 		}
-
-		if state := obj.Response.Checkpoint; len(state) > 0 {
-			name, err := chk.Checkpoint(ctx, "", state)
-			if err != nil {
-				ctxlog.Error(ctx, "protocols.io: failed to save checkpoint", "name", name, "err", err)
-			} else {
-				ctxlog.Info(ctx, "protocols.io: checkpoint", "name", name)
-			}
-		}
 	}
 	return store.Finish(ctx)
 }
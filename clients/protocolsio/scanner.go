@@ -6,6 +6,7 @@ package protocolsio
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -19,11 +20,12 @@ type paginator struct {
 	completedPage int64
 	currentPage   int64
 	totalPages    int64
+	restored      bool
 	PaginatorOptions
 }
 
 func (pg *paginator) urlfor(page int64, first bool) string {
-	if first && pg.From != 0 {
+	if first && pg.From != 0 && !pg.restored {
 		page = int64(pg.From)
 	}
 	pg.Parameters.Set("page_id", strconv.FormatInt(page, 10))
@@ -32,6 +34,10 @@ func (pg *paginator) urlfor(page int64, first bool) string {
 
 func (pg *paginator) Next(_ context.Context, t protocolsiosdk.ListProtocolsV3, r *http.Response) (req *http.Request, done bool, err error) {
 	if r == nil {
+		if pg.restored && pg.completed() {
+			done = true
+			return
+		}
 		req, err = http.NewRequest("GET", pg.urlfor(pg.currentPage, true), nil)
 		return
 	}
@@ -60,6 +66,56 @@ func (pg *paginator) Next(_ context.Context, t protocolsiosdk.ListProtocolsV3, r
 	return
 }
 
+func (pg *paginator) completed() bool {
+	return (pg.totalPages > 0 && pg.completedPage >= pg.totalPages) ||
+		(pg.To != 0 && pg.completedPage >= int64(pg.To))
+}
+
+// MarshalState implements operations.ResumablePaginator. The state is
+// encoded as a JSON Checkpoint.
+func (pg *paginator) MarshalState() ([]byte, error) {
+	return json.Marshal(Checkpoint{
+		CompletedPage: pg.completedPage,
+		CurrentPage:   pg.currentPage,
+		TotalPages:    pg.totalPages,
+	})
+}
+
+// UnmarshalState implements operations.ResumablePaginator. A restored
+// paginator resumes from the checkpoint's current page, ignoring
+// PaginatorOptions.From.
+func (pg *paginator) UnmarshalState(buf []byte) error {
+	var cp Checkpoint
+	if err := json.Unmarshal(buf, &cp); err != nil {
+		return err
+	}
+	pg.completedPage = cp.CompletedPage
+	pg.currentPage = max(cp.CurrentPage, 1)
+	pg.totalPages = cp.TotalPages
+	pg.restored = true
+	return nil
+}
+
+// Upcoming implements operations.PrefetchPaginator. The requests for all
+// of the remaining pages, up to the configured To page if any, are
+// determined from the pagination details in the first response.
+func (pg *paginator) Upcoming(_ context.Context, t protocolsiosdk.ListProtocolsV3, _ *http.Response) ([]*http.Request, error) {
+	p := t.Pagination
+	last := p.TotalPages
+	if pg.To != 0 && int64(pg.To) < last {
+		last = int64(pg.To)
+	}
+	var reqs []*http.Request
+	for page := p.CurrentPage + 1; page <= last; page++ {
+		req, err := http.NewRequest("GET", pg.urlfor(page, false), nil)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
 type PaginatorOptions struct {
 	EndpointURL string
 	Parameters  url.Values
@@ -0,0 +1,259 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"cloudeng.io/logging/ctxlog"
+)
+
+// AdaptiveRateConfig represents the configuration of an
+// AdaptiveRateController. All rates are in requests per second.
+type AdaptiveRateConfig struct {
+	// MinRate and MaxRate bound the request rate, the defaults are
+	// 0.1 and 10 requests per second.
+	MinRate, MaxRate float64
+	// InitialRate is the request rate used until the first response is
+	// received, the default is MaxRate.
+	InitialRate float64
+	// Increase is the amount by which the rate is increased following a
+	// response that indicates ample remaining quota, the default is 10%
+	// of MaxRate.
+	Increase float64
+	// Decrease is the factor by which the rate is multiplied following
+	// a response that indicates that the quota is close to being, or
+	// has been, exhausted, the default is 0.5.
+	Decrease float64
+	// LowWatermark is the fraction of the quota limit below which the
+	// remaining quota is considered to be close to exhausted, the
+	// default is 0.1. It is only used for responses that report both
+	// the remaining quota and the quota limit.
+	LowWatermark float64
+	// Headers specifies the headers used to obtain the remaining quota,
+	// quota limit and reset time, the default is DefaultRateLimitHeaders.
+	Headers *RateLimitHeaders
+}
+
+func (c *AdaptiveRateConfig) setDefaults() {
+	if c.MaxRate <= 0 {
+		c.MaxRate = 10
+	}
+	if c.MinRate <= 0 {
+		c.MinRate = min(0.1, c.MaxRate)
+	}
+	if c.InitialRate <= 0 {
+		c.InitialRate = c.MaxRate
+	}
+	c.InitialRate = min(max(c.InitialRate, c.MinRate), c.MaxRate)
+	if c.Increase <= 0 {
+		c.Increase = c.MaxRate / 10
+	}
+	if c.Decrease <= 0 || c.Decrease >= 1 {
+		c.Decrease = 0.5
+	}
+	if c.LowWatermark <= 0 || c.LowWatermark >= 1 {
+		c.LowWatermark = 0.1
+	}
+	if c.Headers == nil {
+		h := DefaultRateLimitHeaders()
+		c.Headers = &h
+	}
+}
+
+// AdaptiveRateController paces requests at a rate that is adjusted,
+// using an additive-increase/multiplicative-decrease (AIMD) policy, in
+// response to the remaining quota and reset time reported by the server
+// in each response's headers. The aim is to slow down before the quota is
+// exhausted rather than relying on backing off once requests are rejected:
+//
+//   - the rate is reduced multiplicatively when a response is rejected
+//     with a 429 Too Many Requests or 503 Service Unavailable status code,
+//     or when the remaining quota falls below the low watermark;
+//   - the rate is otherwise increased additively;
+//   - the rate never exceeds that required to spread the remaining quota
+//     evenly over the time until the quota is reset;
+//   - requests are paused until the quota is reset when it is exhausted.
+//
+// An AdaptiveRateController may be shared by multiple Endpoints, see
+// WithAdaptiveRateControl, and is safe for concurrent use.
+type AdaptiveRateController struct {
+	cfg AdaptiveRateConfig
+
+	mu          sync.Mutex
+	rate        float64
+	next        time.Time
+	pausedUntil time.Time
+}
+
+// NewAdaptiveRateController returns a new AdaptiveRateController.
+func NewAdaptiveRateController(cfg AdaptiveRateConfig) *AdaptiveRateController {
+	cfg.setDefaults()
+	return &AdaptiveRateController{cfg: cfg, rate: cfg.InitialRate}
+}
+
+// Rate returns the current request rate in requests per second.
+func (a *AdaptiveRateController) Rate() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rate
+}
+
+// Wait blocks until the next request may be issued, or the context
+// is canceled.
+func (a *AdaptiveRateController) Wait(ctx context.Context) error {
+	a.mu.Lock()
+	now := time.Now()
+	at := latest(now, a.next, a.pausedUntil)
+	a.next = at.Add(time.Duration(float64(time.Second) / a.rate))
+	a.mu.Unlock()
+	delay := at.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+	}
+	return nil
+}
+
+func latest(times ...time.Time) time.Time {
+	var l time.Time
+	for _, t := range times {
+		if t.After(l) {
+			l = t
+		}
+	}
+	return l
+}
+
+// Observe adjusts the request rate given a response received from
+// the server.
+func (a *AdaptiveRateController) Observe(ctx context.Context, resp *http.Response) {
+	now := time.Now()
+	quota := a.cfg.Headers.Quota(resp, now)
+	throttled := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	prev := a.rate
+	switch {
+	case throttled:
+		a.rate *= a.cfg.Decrease
+		if delay, ok := a.cfg.Headers.Delay(resp, now); ok {
+			a.pausedUntil = latest(a.pausedUntil, now.Add(delay))
+		}
+	case quota.Remaining == 0:
+		a.rate *= a.cfg.Decrease
+		if quota.Reset > 0 {
+			a.pausedUntil = latest(a.pausedUntil, now.Add(quota.Reset))
+		}
+	case quota.Remaining > 0 && quota.Limit > 0 && quota.Remaining < quota.Limit*a.cfg.LowWatermark:
+		a.rate *= a.cfg.Decrease
+	default:
+		a.rate += a.cfg.Increase
+	}
+	if quota.Remaining > 0 && quota.Reset > 0 {
+		a.rate = min(a.rate, quota.Remaining/quota.Reset.Seconds())
+	}
+	a.rate = min(max(a.rate, a.cfg.MinRate), a.cfg.MaxRate)
+	if a.rate < prev {
+		ctxlog.Info(ctx, "adaptive rate control: reducing request rate", slog.Group("rate", "status", resp.StatusCode, "from", prev, "to", a.rate, "remaining", quota.Remaining, "reset", quota.Reset))
+	}
+}
+
+// WithAdaptiveRateControl specifies an AdaptiveRateController to be used
+// to pace all requests, including retries, in addition to any rate
+// controller specified via WithRateController or WithSharedRateControl.
+// The same AdaptiveRateController should be used for all Endpoints that
+// share a quota, see WithSharedAdaptiveRateControl.
+// WithAdaptiveRateControl and WithSharedAdaptiveRateControl are mutually
+// exclusive, the last one specified takes effect.
+func WithAdaptiveRateControl(a *AdaptiveRateController) Option {
+	return func(o *options) {
+		o.adaptiveRateController = a
+		o.sharedAdaptiveRateControl = nil
+	}
+}
+
+// AdaptiveRateControllers is a registry of AdaptiveRateControllers keyed
+// by host and API key ID. It allows for all of the Endpoints that access
+// the same service with the same credentials to share a single adaptive
+// rate, see WithSharedAdaptiveRateControl.
+type AdaptiveRateControllers struct {
+	mu          sync.Mutex
+	controllers map[rateControllerKey]*AdaptiveRateController
+}
+
+// NewAdaptiveRateControllers returns a new, empty, AdaptiveRateControllers.
+func NewAdaptiveRateControllers() *AdaptiveRateControllers {
+	return &AdaptiveRateControllers{controllers: map[rateControllerKey]*AdaptiveRateController{}}
+}
+
+// DefaultAdaptiveRateControllers is the process-wide AdaptiveRateControllers
+// used by WithSharedAdaptiveRateControl when no registry is specified.
+var DefaultAdaptiveRateControllers = NewAdaptiveRateControllers()
+
+// Get returns the controller for the specified host and key ID, creating
+// it by calling newController if there is no such controller. If
+// newController is nil, or returns nil, a controller created using a zero
+// value AdaptiveRateConfig is used. The host may include a port.
+func (r *AdaptiveRateControllers) Get(host, keyID string, newController func() *AdaptiveRateController) *AdaptiveRateController {
+	key := newRateControllerKey(host, keyID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a, ok := r.controllers[key]; ok {
+		return a
+	}
+	var a *AdaptiveRateController
+	if newController != nil {
+		a = newController()
+	}
+	if a == nil {
+		a = NewAdaptiveRateController(AdaptiveRateConfig{})
+	}
+	r.controllers[key] = a
+	return a
+}
+
+type sharedAdaptiveRateControl struct {
+	registry      *AdaptiveRateControllers
+	keyID         string
+	newController func() *AdaptiveRateController
+}
+
+// WithSharedAdaptiveRateControl specifies that the AdaptiveRateController
+// used for each request is to be obtained from the supplied registry, or
+// DefaultAdaptiveRateControllers if registry is nil, using the request's
+// host and the specified API key ID. The newController function, if
+// non-nil, is used to create the controller for a host and key ID that is
+// not already in the registry.
+func WithSharedAdaptiveRateControl(registry *AdaptiveRateControllers, keyID string, newController func() *AdaptiveRateController) Option {
+	return func(o *options) {
+		if registry == nil {
+			registry = DefaultAdaptiveRateControllers
+		}
+		o.adaptiveRateController = nil
+		o.sharedAdaptiveRateControl = &sharedAdaptiveRateControl{
+			registry:      registry,
+			keyID:         keyID,
+			newController: newController,
+		}
+	}
+}
+
+// adaptiveRateControllerFor returns the adaptive rate controller, if any,
+// to use for req.
+func (ep *Endpoint[T]) adaptiveRateControllerFor(req *http.Request) *AdaptiveRateController {
+	if src := ep.sharedAdaptiveRateControl; src != nil {
+		return src.registry.Get(req.URL.Host, src.keyID, src.newController)
+	}
+	return ep.adaptiveRateController
+}
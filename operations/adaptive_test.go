@@ -0,0 +1,131 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func quotaResponse(status int, headers ...string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}}
+	for i := 0; i < len(headers); i += 2 {
+		resp.Header.Set(headers[i], headers[i+1])
+	}
+	return resp
+}
+
+func TestAdaptiveRateController(t *testing.T) {
+	ctx := context.Background()
+	arc := operations.NewAdaptiveRateController(operations.AdaptiveRateConfig{
+		MinRate:     1,
+		MaxRate:     100,
+		InitialRate: 10,
+		Increase:    5,
+	})
+	for i, tc := range []struct {
+		resp *http.Response
+		rate float64
+	}{
+		// Ample quota: additive increase.
+		{quotaResponse(http.StatusOK, "X-RateLimit-Remaining", "900", "X-RateLimit-Limit", "1000"), 15},
+		{quotaResponse(http.StatusOK), 20},
+		// Below the low watermark: multiplicative decrease.
+		{quotaResponse(http.StatusOK, "X-RateLimit-Remaining", "50", "X-RateLimit-Limit", "1000"), 10},
+		// Throttled: multiplicative decrease.
+		{quotaResponse(http.StatusTooManyRequests), 5},
+		{quotaResponse(http.StatusServiceUnavailable), 2.5},
+		// Limited to spreading the remaining quota over the reset period.
+		{quotaResponse(http.StatusOK, "RateLimit-Remaining", "60", "RateLimit-Reset", "30"), 2},
+		// Bounded by MinRate and MaxRate.
+		{quotaResponse(http.StatusTooManyRequests), 1},
+		{quotaResponse(http.StatusOK, "RateLimit-Remaining", "1", "RateLimit-Reset", "30"), 1},
+	} {
+		arc.Observe(ctx, tc.resp)
+		if got, want := arc.Rate(), tc.rate; got != want {
+			t.Errorf("%v: got %v, want %v", i, got, want)
+		}
+	}
+	for range 100 {
+		arc.Observe(ctx, quotaResponse(http.StatusOK))
+	}
+	if got, want := arc.Rate(), 100.0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAdaptiveRateControl(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var times []time.Time
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		times = append(times, time.Now())
+		if len(times) == 1 {
+			// Quota exhausted, to be reset in 100ms.
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "0.1")
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	arc := operations.NewAdaptiveRateController(operations.AdaptiveRateConfig{MaxRate: 1000})
+	for range 2 {
+		ep := operations.NewEndpoint[example](operations.WithAdaptiveRateControl(arc))
+		if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := times[1].Sub(times[0]), 100*time.Millisecond; got < want {
+		t.Errorf("got %v, want >= %v", got, want)
+	}
+	// Halved by the first response, increased by 10% of MaxRate by the second.
+	if got, want := arc.Rate(), 600.0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSharedAdaptiveRateControl(t *testing.T) {
+	ctx := context.Background()
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("X-RateLimit-Limit", "100")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	registry := operations.NewAdaptiveRateControllers()
+	created := 0
+	newController := func() *operations.AdaptiveRateController {
+		created++
+		return operations.NewAdaptiveRateController(operations.AdaptiveRateConfig{MaxRate: 1000})
+	}
+	for range 2 {
+		ep := operations.NewEndpoint[example](operations.WithSharedAdaptiveRateControl(registry, "key", newController))
+		if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := created, 1; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	u, _ := url.Parse(srv.URL)
+	// Halved by each of the two responses.
+	if got, want := registry.Get(u.Host, "key", nil).Rate(), 250.0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := registry.Get(u.Host, "other", nil), registry.Get(u.Host, "key", nil); got == want {
+		t.Errorf("controllers for different keys should differ")
+	}
+}
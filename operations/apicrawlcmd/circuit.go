@@ -0,0 +1,82 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package apicrawlcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloudeng.io/logging/ctxlog"
+	"cloudeng.io/webapi/operations"
+)
+
+// CircuitBreaker represents the configuration of the per-host circuit
+// breakers used to stop a crawl from repeatedly retrying requests to an
+// API that is experiencing an outage.
+type CircuitBreaker struct {
+	FailureThreshold int           `yaml:"failure_threshold" cmd:"number of consecutive failures (network errors or 5xx status codes) that open the circuit breaker for a host, zero disables the circuit breaker"`
+	CoolDown         time.Duration `yaml:"cool_down" cmd:"how long the circuit breaker stays open before allowing probe requests, defaults to one minute"`
+	HalfOpenProbes   int           `yaml:"half_open_probes" cmd:"number of probe requests that must succeed to close the circuit breaker, defaults to 1"`
+}
+
+// NewCircuitBreakers returns the operations.CircuitBreakers described by
+// the configuration or nil if circuit breakers are not enabled.
+func (c CircuitBreaker) NewCircuitBreakers() *operations.CircuitBreakers {
+	if c.FailureThreshold <= 0 {
+		return nil
+	}
+	return operations.NewCircuitBreakers(operations.CircuitBreakerConfig{
+		FailureThreshold: c.FailureThreshold,
+		CoolDown:         c.CoolDown,
+		HalfOpenProbes:   c.HalfOpenProbes,
+	})
+}
+
+var (
+	circuitBreakersMu sync.Mutex
+	circuitBreakers   = map[CircuitBreaker]*operations.CircuitBreakers{}
+)
+
+// Options returns the operations.Option required to use the configured
+// circuit breakers, if any. The circuit breakers are created once per
+// process for each configuration so that all Endpoints created using
+// options returned by any call to Options for the same configuration
+// share the circuit breaker for each host.
+func (c CircuitBreaker) Options() []operations.Option {
+	if c.FailureThreshold <= 0 {
+		return nil
+	}
+	circuitBreakersMu.Lock()
+	defer circuitBreakersMu.Unlock()
+	cb, ok := circuitBreakers[c]
+	if !ok {
+		cb = c.NewCircuitBreakers()
+		circuitBreakers[c] = cb
+	}
+	return []operations.Option{operations.WithCircuitBreakers(cb)}
+}
+
+// ErrCrawlIncomplete is returned, wrapping the original error, by
+// StoppedByCircuitBreaker when a crawl was terminated because a circuit
+// breaker opened.
+var ErrCrawlIncomplete = errors.New("crawl incomplete")
+
+// StoppedByCircuitBreaker returns err wrapped with ErrCrawlIncomplete, and
+// logs the host concerned, if err indicates that a crawl was terminated
+// because a circuit breaker opened, and err otherwise. A crawl so
+// terminated must not be reported as having succeeded; crawls that
+// support checkpoints should save their progress before returning the
+// error so that they may be resumed once the API is available again.
+func StoppedByCircuitBreaker(ctx context.Context, err error) error {
+	var coerr *operations.CircuitOpenError
+	if !errors.As(err, &coerr) {
+		return err
+	}
+	ctxlog.Info(ctx, "crawl stopped: circuit breaker is open", "host", coerr.Host, "until", coerr.Until)
+	return fmt.Errorf("%w: %w", ErrCrawlIncomplete, err)
+}
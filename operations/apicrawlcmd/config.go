@@ -8,10 +8,12 @@ package apicrawlcmd
 
 import (
 	"context"
+	"time"
 
 	"cloudeng.io/file/checkpoint"
 	"cloudeng.io/file/crawl/crawlcmd"
 	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/operations/apitokens"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,6 +24,9 @@ import (
 type Crawl[T any] struct {
 	RateControl crawlcmd.RateControl      `yaml:",inline"`
 	Cache       crawlcmd.CrawlCacheConfig `yaml:"cache"`
+	HTTPClient  HTTPClient                `yaml:"http_client" cmd:"configuration for the http.Client used for API requests"`
+	Circuit     CircuitBreaker            `yaml:"circuit_breaker" cmd:"configuration for per-host circuit breakers"`
+	Adaptive    AdaptiveRateControl       `yaml:"adaptive_rate_control" cmd:"configuration for adaptive rate control based on the quota reported by the server"`
 	KeyID       string                    `yaml:"key_id" cmd:"identifier of the API key to use for this crawl"`
 	Service     T                         `yaml:"service_config" cmd:"service specific configuration"`
 }
@@ -34,6 +39,9 @@ type Crawls map[string]Crawl[yaml.Node]
 func ParseCrawlConfig[T any](cfg Crawl[yaml.Node], service *Crawl[T]) error {
 	service.RateControl = cfg.RateControl
 	service.Cache = cfg.Cache
+	service.HTTPClient = cfg.HTTPClient
+	service.Circuit = cfg.Circuit
+	service.Adaptive = cfg.Adaptive
 	service.KeyID = cfg.KeyID
 	if err := cfg.Service.Decode(&service.Service); err != nil {
 		return err
@@ -64,6 +72,10 @@ func (r Resources) CreateResources(ctx context.Context, cfg crawlcmd.CrawlCacheC
 	return
 }
 
+// KeyExpiryWarning is the period before a crawl's API key expires, as
+// recorded by an apitokens.Loader, during which NewState logs a warning.
+var KeyExpiryWarning = 7 * 24 * time.Hour
+
 type State[T any] struct {
 	Config     Crawl[T]
 	Store      operations.FS
@@ -76,6 +88,9 @@ func NewState[T any](ctx context.Context, config Crawl[yaml.Node], resources Res
 	if err != nil {
 		return State[T]{}, err
 	}
+	if len(s.Config.KeyID) > 0 {
+		apitokens.WarnExpiring(ctx, KeyExpiryWarning, s.Config.KeyID)
+	}
 	s.Store, s.Checkpoint, err = resources.CreateResources(ctx, s.Config.Cache)
 	if err != nil {
 		return State[T]{}, err
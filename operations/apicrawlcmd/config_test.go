@@ -5,10 +5,17 @@
 package apicrawlcmd_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"cloudeng.io/cmdutil/cmdyaml"
+	"cloudeng.io/webapi/operations"
 	"cloudeng.io/webapi/operations/apicrawlcmd"
 )
 
@@ -76,3 +83,142 @@ func TestAPICrawlConfig(t *testing.T) {
 	}
 
 }
+
+const httpClientSpec = `
+api1:
+  http_client:
+    timeout: 30s
+    max_conns_per_host: 4
+    disable_http2: true
+`
+
+func TestHTTPClientConfig(t *testing.T) {
+	var crawls apicrawlcmd.Crawls
+	if err := cmdyaml.ParseConfigString(httpClientSpec, &crawls); err != nil {
+		t.Fatal(err)
+	}
+	var a1 apicrawlcmd.Crawl[api1]
+	if err := apicrawlcmd.ParseCrawlConfig(crawls["api1"], &a1); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if got, want := a1.HTTPClient.Timeout, 30*time.Second; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	client, err := a1.HTTPClient.NewHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := client.Timeout, 30*time.Second; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	transport := client.Transport.(*http.Transport)
+	if got, want := transport.MaxConnsPerHost, 4; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if transport.ForceAttemptHTTP2 {
+		t.Errorf("expected HTTP/2 to be disabled")
+	}
+
+	a1.HTTPClient.CACertsFile = "does-not-exist.pem"
+	if _, err := a1.HTTPClient.NewHTTPClient(); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+const circuitSpec = `
+api1:
+  circuit_breaker:
+    failure_threshold: 3
+    cool_down: 5m
+  adaptive_rate_control:
+    max_rate: 20
+    low_watermark: 0.2
+api2:
+  service_config:
+    else: 2
+`
+
+func TestCircuitBreakerConfig(t *testing.T) {
+	ctx := context.Background()
+	var crawls apicrawlcmd.Crawls
+	if err := cmdyaml.ParseConfigString(circuitSpec, &crawls); err != nil {
+		t.Fatal(err)
+	}
+	var a1 apicrawlcmd.Crawl[api1]
+	if err := apicrawlcmd.ParseCrawlConfig(crawls["api1"], &a1); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if got, want := a1.Circuit.CoolDown, 5*time.Minute; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := len(a1.Circuit.Options()), 1; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := a1.Adaptive.LowWatermark, 0.2; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := a1.Adaptive.NewAdaptiveRateController().Rate(), 20.0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	var a2 apicrawlcmd.Crawl[api2]
+	if err := apicrawlcmd.ParseCrawlConfig(crawls["api2"], &a2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if a2.Circuit.NewCircuitBreakers() != nil {
+		t.Errorf("circuit breakers should not be enabled")
+	}
+	if a2.Adaptive.Options() != nil {
+		t.Errorf("adaptive rate control should not be enabled")
+	}
+
+	err := &operations.Error{Err: &operations.CircuitOpenError{Host: "example.com"}}
+	if err := apicrawlcmd.StoppedByCircuitBreaker(ctx, fmt.Errorf("crawl: %w", err)); !errors.Is(err, apicrawlcmd.ErrCrawlIncomplete) || !errors.Is(err, operations.ErrCircuitOpen) {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+	other := errors.New("other")
+	if got, want := apicrawlcmd.StoppedByCircuitBreaker(ctx, other), other; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSharedCircuitBreakers(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	// Endpoints created using separately obtained options must share
+	// their circuit breakers.
+	circuit := apicrawlcmd.CircuitBreaker{FailureThreshold: 1, CoolDown: time.Hour}
+	if _, _, _, err := operations.NewEndpoint[string](circuit.Options()...).Get(ctx, srv.URL); !errors.Is(err, operations.ErrServerError) {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+	if _, _, _, err := operations.NewEndpoint[string](circuit.Options()...).Get(ctx, srv.URL); !errors.Is(err, operations.ErrCircuitOpen) {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+}
+
+func TestSharedAdaptiveRateControl(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("X-RateLimit-Limit", "100")
+		_, _ = w.Write([]byte(`"ok"`))
+	}))
+	defer srv.Close()
+
+	// Endpoints created using separately obtained options must share
+	// their adaptive rate controller.
+	adaptive := apicrawlcmd.AdaptiveRateControl{MaxRate: 1000}
+	for range 2 {
+		if _, _, _, err := operations.NewEndpoint[string](adaptive.Options()...).Get(ctx, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u, _ := url.Parse(srv.URL)
+	// Halved by each of the two responses.
+	if got, want := operations.DefaultAdaptiveRateControllers.Get(u.Host, "", nil).Rate(), 250.0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
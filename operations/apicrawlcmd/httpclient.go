@@ -0,0 +1,105 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package apicrawlcmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// HTTPClient represents the configuration of the http.Client, and its
+// underlying http.Transport, used to make API requests. The zero value
+// results in a client that is equivalent to http.DefaultClient.
+type HTTPClient struct {
+	Timeout             time.Duration `yaml:"timeout" cmd:"timeout for each request attempt, zero for no timeout"`
+	ProxyURL            string        `yaml:"proxy_url" cmd:"URL of the proxy to use for all requests, if not set the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used"`
+	CACertsFile         string        `yaml:"ca_certs" cmd:"PEM file containing CA certificates to trust in addition to the system's CA certificates"`
+	ClientCertFile      string        `yaml:"client_cert" cmd:"PEM file containing a client certificate for use with mTLS"`
+	ClientKeyFile       string        `yaml:"client_key" cmd:"PEM file containing the private key for client_cert"`
+	MaxIdleConns        int           `yaml:"max_idle_conns" cmd:"maximum number of idle connections across all hosts, zero for no limit"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host" cmd:"maximum number of idle connections per host, zero for the net/http default"`
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host" cmd:"maximum number of connections per host, zero for no limit"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout" cmd:"how long an idle connection is kept open, zero for the net/http default"`
+	TLSHandshakeTimeout time.Duration `yaml:"tls_handshake_timeout" cmd:"timeout for TLS handshakes, zero for the net/http default"`
+	DisableHTTP2        bool          `yaml:"disable_http2" cmd:"if true, HTTP/2 is not used"`
+}
+
+// NewHTTPClient creates a new http.Client using the configuration. The
+// client's transport is a clone of http.DefaultTransport with the configured
+// settings applied.
+func (c HTTPClient) NewHTTPClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(c.ProxyURL) > 0 {
+		u, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL: %q: %w", c.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	if c.MaxIdleConns > 0 {
+		transport.MaxIdleConns = c.MaxIdleConns
+	}
+	if c.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = c.MaxIdleConnsPerHost
+	}
+	if c.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = c.MaxConnsPerHost
+	}
+	if c.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = c.IdleConnTimeout
+	}
+	if c.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = c.TLSHandshakeTimeout
+	}
+	if c.DisableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   c.Timeout,
+	}, nil
+}
+
+func (c HTTPClient) tlsConfig() (*tls.Config, error) {
+	if len(c.CACertsFile) == 0 && len(c.ClientCertFile) == 0 {
+		return nil, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(c.CACertsFile) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(c.CACertsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificates: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no CA certificates found in %v", c.CACertsFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if len(c.ClientCertFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
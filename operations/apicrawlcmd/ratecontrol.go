@@ -0,0 +1,70 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package apicrawlcmd
+
+import (
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+)
+
+// SharedRateControl returns an operations.Option that configures an
+// Endpoint to share its rate controller with all other Endpoints that
+// access the same host using the crawl's KeyID, see
+// operations.WithSharedRateControl. The controller is obtained from the
+// supplied registry, or operations.DefaultRateControllers if registry is
+// nil, and is created using the crawl's RateControl configuration when
+// first required. Any error in that configuration is returned immediately.
+func (c Crawl[T]) SharedRateControl(registry *operations.RateControllers) (operations.Option, error) {
+	if _, err := c.RateControl.NewRateController(); err != nil {
+		return nil, err
+	}
+	cfg := c.RateControl
+	newController := func() *ratecontrol.Controller {
+		rc, _ := cfg.NewRateController()
+		return rc
+	}
+	return operations.WithSharedRateControl(registry, c.KeyID, newController, cfg.ExponentialBackoff.StatusCodes...), nil
+}
+
+// AdaptiveRateControl represents the configuration of adaptive rate
+// control, see operations.AdaptiveRateController.
+type AdaptiveRateControl struct {
+	MaxRate      float64 `yaml:"max_rate" cmd:"maximum number of requests per second, zero disables adaptive rate control"`
+	MinRate      float64 `yaml:"min_rate" cmd:"minimum number of requests per second, defaults to 0.1"`
+	InitialRate  float64 `yaml:"initial_rate" cmd:"number of requests per second used until the first response is received, defaults to max_rate"`
+	Increase     float64 `yaml:"increase" cmd:"number of requests per second by which the rate is increased when ample quota remains, defaults to 10% of max_rate"`
+	Decrease     float64 `yaml:"decrease" cmd:"factor by which the rate is multiplied when the quota is close to being exhausted or requests are throttled, defaults to 0.5"`
+	LowWatermark float64 `yaml:"low_watermark" cmd:"fraction of the quota limit below which the remaining quota is considered close to exhausted, defaults to 0.1"`
+}
+
+// NewAdaptiveRateController returns the operations.AdaptiveRateController
+// described by the configuration or nil if adaptive rate control is not
+// enabled.
+func (c AdaptiveRateControl) NewAdaptiveRateController() *operations.AdaptiveRateController {
+	if c.MaxRate <= 0 {
+		return nil
+	}
+	return operations.NewAdaptiveRateController(operations.AdaptiveRateConfig{
+		MinRate:      c.MinRate,
+		MaxRate:      c.MaxRate,
+		InitialRate:  c.InitialRate,
+		Increase:     c.Increase,
+		Decrease:     c.Decrease,
+		LowWatermark: c.LowWatermark,
+	})
+}
+
+// Options returns the operations.Option required to use adaptive rate
+// control, if enabled. The operations.AdaptiveRateController for each host
+// is obtained from operations.DefaultAdaptiveRateControllers, and created
+// using the configuration when first required, so that all Endpoints
+// created using options returned by any call to Options share the same
+// controller for each host.
+func (c AdaptiveRateControl) Options() []operations.Option {
+	if c.MaxRate <= 0 {
+		return nil
+	}
+	return []operations.Option{operations.WithSharedAdaptiveRateControl(nil, "", c.NewAdaptiveRateController)}
+}
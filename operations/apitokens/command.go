@@ -0,0 +1,93 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package apitokens
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Command is a Loader that obtains tokens by running an external helper
+// command, in the style of git credential helpers. The command is run once
+// for each key ID with "get" appended to Args and is supplied with:
+//
+//	key_id=<id>
+//
+// followed by a blank line on its standard input. It must write the token
+// and its metadata to its standard output as key=value lines:
+//
+//	token=<token>                 (or password=<token>)
+//	user=<user>                   (or username=<user>)
+//	expires=<RFC 3339 time>       (or password_expiry_utc=<unix seconds>)
+//	scopes=<comma or space separated list>
+//
+// Unrecognised lines are ignored and a helper that outputs no token is
+// treated as an error.
+type Command struct {
+	Path   string
+	Args   []string
+	KeyIDs []string
+}
+
+// Load implements Loader.
+func (c Command) Load(ctx context.Context) ([]Entry, error) {
+	entries := make([]Entry, 0, len(c.KeyIDs))
+	for _, id := range c.KeyIDs {
+		entry, err := c.get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (c Command) get(ctx context.Context, id string) (Entry, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Path, append(append([]string(nil), c.Args...), "get")...)
+	cmd.Stdin = strings.NewReader("key_id=" + id + "\n\n")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return Entry{}, fmt.Errorf("credential helper %v for %q failed: %w: %s", c.Path, id, err, strings.TrimSpace(stderr.String()))
+	}
+	defer ClearToken(out)
+	entry := Entry{ID: id}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "token", "password":
+			entry.Token = []byte(v)
+		case "user", "username":
+			entry.User = v
+		case "expires":
+			err = entry.setExpires(v)
+		case "password_expiry_utc":
+			var secs int64
+			if secs, err = strconv.ParseInt(v, 10, 64); err == nil {
+				entry.Expires = time.Unix(secs, 0).UTC()
+			}
+		case "scopes":
+			entry.Scopes = parseScopes(v)
+		}
+		if err != nil {
+			return Entry{}, fmt.Errorf("credential helper %v for %q: invalid %v: %w", c.Path, id, k, err)
+		}
+	}
+	if len(entry.Token) == 0 {
+		return Entry{}, NewErrNotFound(id, "credential helper "+c.Path)
+	}
+	return entry, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package apitokens
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultKeyringIterations is the number of PBKDF2 iterations used by
+// WriteKeyring to derive the encryption key from the passphrase.
+const DefaultKeyringIterations = 600_000
+
+// Keyring is a Loader that obtains tokens from a local keyring file that
+// is encrypted using AES-256-GCM with a key derived from a passphrase
+// using PBKDF2-HMAC-SHA256, see WriteKeyring.
+type Keyring struct {
+	Path string
+	// Passphrase returns the passphrase for the keyring, for example by
+	// prompting the user or by reading it from a file.
+	Passphrase func(ctx context.Context) ([]byte, error)
+}
+
+type keyringFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type keyringEntry struct {
+	ID      string    `json:"key_id"`
+	User    string    `json:"user,omitempty"`
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires,omitzero"`
+	Scopes  []string  `json:"scopes,omitempty"`
+}
+
+const keyringKDF = "pbkdf2-hmac-sha256"
+
+func keyringCipher(passphrase, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, string(passphrase), salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer ClearToken(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Load implements Loader.
+func (k Keyring) Load(ctx context.Context) ([]Entry, error) {
+	buf, err := os.ReadFile(k.Path)
+	if err != nil {
+		return nil, err
+	}
+	var kf keyringFile
+	if err := json.Unmarshal(buf, &kf); err != nil {
+		return nil, fmt.Errorf("keyring %v: %w", k.Path, err)
+	}
+	if kf.Version != 1 || kf.KDF != keyringKDF {
+		return nil, fmt.Errorf("keyring %v: unsupported version %v or kdf %q", k.Path, kf.Version, kf.KDF)
+	}
+	passphrase, err := k.Passphrase(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("keyring %v: failed to obtain passphrase: %w", k.Path, err)
+	}
+	defer ClearToken(passphrase)
+	aead, err := keyringCipher(passphrase, kf.Salt, kf.Iterations)
+	if err != nil {
+		return nil, fmt.Errorf("keyring %v: %w", k.Path, err)
+	}
+	plaintext, err := aead.Open(nil, kf.Nonce, kf.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("keyring %v: failed to decrypt, the passphrase may be incorrect: %w", k.Path, err)
+	}
+	defer ClearToken(plaintext)
+	var stored []keyringEntry
+	if err := json.Unmarshal(plaintext, &stored); err != nil {
+		return nil, fmt.Errorf("keyring %v: %w", k.Path, err)
+	}
+	entries := make([]Entry, len(stored))
+	for i, s := range stored {
+		entries[i] = Entry{
+			ID:       s.ID,
+			User:     s.User,
+			Token:    []byte(s.Token),
+			Metadata: Metadata{Expires: s.Expires, Scopes: s.Scopes},
+		}
+	}
+	return entries, nil
+}
+
+// WriteKeyring writes the supplied entries to an encrypted keyring file,
+// readable only by its owner, that can be read using Keyring.
+func WriteKeyring(path string, passphrase []byte, entries []Entry) error {
+	stored := make([]keyringEntry, len(entries))
+	for i, e := range entries {
+		stored[i] = keyringEntry{
+			ID:      e.ID,
+			User:    e.User,
+			Token:   string(e.Token),
+			Expires: e.Expires,
+			Scopes:  e.Scopes,
+		}
+	}
+	plaintext, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	defer ClearToken(plaintext)
+	kf := keyringFile{
+		Version:    1,
+		KDF:        keyringKDF,
+		Iterations: DefaultKeyringIterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(kf.Salt); err != nil {
+		return err
+	}
+	aead, err := keyringCipher(passphrase, kf.Salt, kf.Iterations)
+	if err != nil {
+		return err
+	}
+	kf.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(kf.Nonce); err != nil {
+		return err
+	}
+	kf.Ciphertext = aead.Seal(nil, kf.Nonce, plaintext, nil)
+	buf, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf, 0600)
+}
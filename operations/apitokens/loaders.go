@@ -0,0 +1,157 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package apitokens
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"cloudeng.io/cmdutil/keys"
+	"cloudeng.io/logging/ctxlog"
+)
+
+// Metadata represents information about a token, such as when it expires
+// and the scopes it grants, that is recorded by a Loader. It is stored as
+// the extra information for the token's keys.Info and hence may also be
+// specified via the 'extra' field of keys read from json or yaml files.
+type Metadata struct {
+	Expires time.Time `json:"expires,omitzero" yaml:"expires,omitempty"`
+	Scopes  []string  `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// ExpiresWithin returns true if the token has an expiry time and it will
+// have expired by now+d.
+func (m Metadata) ExpiresWithin(now time.Time, d time.Duration) bool {
+	return !m.Expires.IsZero() && !m.Expires.After(now.Add(d))
+}
+
+// Entry represents a token obtained by a Loader.
+type Entry struct {
+	ID    string
+	User  string
+	Token []byte
+	Metadata
+}
+
+// Loader represents a source of tokens, such as an encrypted keyring file
+// or environment variables.
+type Loader interface {
+	Load(ctx context.Context) ([]Entry, error)
+}
+
+// Load returns a context that contains the tokens obtained from the
+// supplied loaders in addition to any existing tokens, see ContextWithKey.
+// Loaders are called in order and a token with the same ID as one obtained
+// from an earlier loader replaces it. The token values returned by the
+// loaders are cleared once they have been added to the context.
+func Load(ctx context.Context, loaders ...Loader) (context.Context, error) {
+	for _, l := range loaders {
+		entries, err := l.Load(ctx)
+		if err != nil {
+			return ctx, err
+		}
+		for _, e := range entries {
+			ki := keys.NewInfo(e.ID, e.User, e.Token)
+			ki.WithExtra(e.Metadata)
+			ctx = ContextWithKey(ctx, ki)
+		}
+	}
+	return ctx, nil
+}
+
+// MetadataFromContext returns the Metadata for the specified key ID, if
+// any, that is stored in the context.
+func MetadataFromContext(ctx context.Context, id string) (Metadata, bool) {
+	ki, ok := KeyFromContext(ctx, id)
+	if !ok {
+		return Metadata{}, false
+	}
+	var md Metadata
+	if err := ki.UnmarshalExtra(&md); err != nil {
+		return Metadata{}, false
+	}
+	return md, true
+}
+
+// Expiring returns the IDs of the specified keys, stored in the context,
+// that expire within the specified duration.
+func Expiring(ctx context.Context, within time.Duration, ids ...string) []string {
+	now := time.Now()
+	var expiring []string
+	for _, id := range ids {
+		if md, ok := MetadataFromContext(ctx, id); ok && md.ExpiresWithin(now, within) {
+			expiring = append(expiring, id)
+		}
+	}
+	return expiring
+}
+
+// WarnExpiring logs a warning, via ctxlog, for each of the specified keys
+// that expire within the specified duration and returns true if any do.
+// It is intended to be called before starting a long running crawl.
+func WarnExpiring(ctx context.Context, within time.Duration, ids ...string) bool {
+	expiring := Expiring(ctx, within, ids...)
+	for _, id := range expiring {
+		md, _ := MetadataFromContext(ctx, id)
+		ctxlog.Warn(ctx, "api token expiring", "key_id", id, "expires", md.Expires, "remaining", time.Until(md.Expires).Round(time.Second))
+	}
+	return len(expiring) > 0
+}
+
+// Env is a Loader that obtains tokens from environment variables. Vars
+// maps key IDs to the names of the environment variables containing
+// their tokens. The optional metadata for each token is read from
+// variables with the same name and the following suffixes:
+//
+//	_USER     the user associated with the token
+//	_EXPIRES  the token's expiry time in RFC 3339 format
+//	_SCOPES   a comma or space separated list of scopes
+//
+// Key IDs whose variable is not set are ignored.
+type Env struct {
+	Vars map[string]string
+}
+
+// Load implements Loader.
+func (e Env) Load(_ context.Context) ([]Entry, error) {
+	var entries []Entry
+	for _, id := range slices.Sorted(maps.Keys(e.Vars)) {
+		name := e.Vars[id]
+		token, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		entry := Entry{ID: id, User: os.Getenv(name + "_USER"), Token: []byte(token)}
+		if err := entry.setExpires(os.Getenv(name + "_EXPIRES")); err != nil {
+			return nil, fmt.Errorf("environment variable %v_EXPIRES: %w", name, err)
+		}
+		entry.Scopes = parseScopes(os.Getenv(name + "_SCOPES"))
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (e *Entry) setExpires(v string) error {
+	if v = strings.TrimSpace(v); len(v) == 0 {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return err
+	}
+	e.Expires = t
+	return nil
+}
+
+func parseScopes(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package apitokens_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"cloudeng.io/webapi/operations/apitokens"
+)
+
+func expectToken(t *testing.T, ctx context.Context, id, user, token string, md apitokens.Metadata) {
+	t.Helper()
+	ki, ok := apitokens.KeyFromContext(ctx, id)
+	if !ok {
+		t.Fatalf("key %v not found", id)
+	}
+	if got, want := ki.User, user; got != want {
+		t.Errorf("%v: got %v, want %v", id, got, want)
+	}
+	if got, want := string(ki.Token().Value()), token; got != want {
+		t.Errorf("%v: got %v, want %v", id, got, want)
+	}
+	got, ok := apitokens.MetadataFromContext(ctx, id)
+	if !ok {
+		t.Fatalf("metadata for %v not found", id)
+	}
+	if !got.Expires.Equal(md.Expires) || !slices.Equal(got.Scopes, md.Scopes) {
+		t.Errorf("%v: got %v, want %v", id, got, md)
+	}
+}
+
+func TestEnvLoader(t *testing.T) {
+	t.Setenv("TEST_TOKEN", "secret")
+	t.Setenv("TEST_TOKEN_USER", "alice")
+	t.Setenv("TEST_TOKEN_EXPIRES", "2030-01-02T03:04:05Z")
+	t.Setenv("TEST_TOKEN_SCOPES", "read, write")
+	ctx, err := apitokens.Load(context.Background(),
+		apitokens.Env{Vars: map[string]string{"t1": "TEST_TOKEN", "t2": "TEST_TOKEN_UNSET"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectToken(t, ctx, "t1", "alice", "secret", apitokens.Metadata{
+		Expires: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		Scopes:  []string{"read", "write"},
+	})
+	if _, ok := apitokens.KeyFromContext(ctx, "t2"); ok {
+		t.Errorf("t2 should not have been loaded")
+	}
+}
+
+func TestKeyringLoader(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := apitokens.WriteKeyring(path, []byte("passphrase"), []apitokens.Entry{
+		{ID: "k1", User: "u1", Token: []byte("t1"), Metadata: apitokens.Metadata{Expires: expires, Scopes: []string{"s1"}}},
+		{ID: "k2", Token: []byte("t2")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(buf), "t1") {
+		t.Errorf("keyring is not encrypted: %s", buf)
+	}
+
+	passphrase := func(pp string) func(context.Context) ([]byte, error) {
+		return func(context.Context) ([]byte, error) { return []byte(pp), nil }
+	}
+	ctx, err = apitokens.Load(ctx, apitokens.Keyring{Path: path, Passphrase: passphrase("passphrase")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectToken(t, ctx, "k1", "u1", "t1", apitokens.Metadata{Expires: expires, Scopes: []string{"s1"}})
+	expectToken(t, ctx, "k2", "", "t2", apitokens.Metadata{})
+
+	_, err = apitokens.Load(ctx, apitokens.Keyring{Path: path, Passphrase: passphrase("wrong")})
+	if err == nil || !strings.Contains(err.Error(), "failed to decrypt") {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+}
+
+func TestPassDirLoader(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".gpg-id", "someone@example.com\n")
+	write("github/api.gpg", "encrypted")
+	write("plain", "t2\nlogin: bob\nexpires: 2030-01-02T03:04:05Z\nscopes: a b\n")
+
+	decrypt := func(_ context.Context, filename string) ([]byte, error) {
+		if got, want := filename, filepath.Join(dir, "github", "api.gpg"); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		return []byte("t1\nuser: alice\n"), nil
+	}
+	ctx, err := apitokens.Load(context.Background(), apitokens.PassDir{Dir: dir, Decrypt: decrypt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectToken(t, ctx, "github/api", "alice", "t1", apitokens.Metadata{})
+	expectToken(t, ctx, "plain", "bob", "t2", apitokens.Metadata{
+		Expires: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		Scopes:  []string{"a", "b"},
+	})
+	if _, ok := apitokens.KeyFromContext(ctx, ".gpg-id"); ok {
+		t.Errorf(".gpg-id should not have been loaded")
+	}
+}
+
+func TestCommandLoader(t *testing.T) {
+	script := `test "$1" = get || exit 1
+read line
+case "$line" in
+key_id=k1) printf 'username=carol\npassword=t1\npassword_expiry_utc=1893553445\nscopes=x,y\n';;
+key_id=k2) echo token=t2;;
+esac`
+	ctx, err := apitokens.Load(context.Background(), apitokens.Command{
+		Path:   "sh",
+		Args:   []string{"-c", script, "helper"},
+		KeyIDs: []string{"k1", "k2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectToken(t, ctx, "k1", "carol", "t1", apitokens.Metadata{
+		Expires: time.Unix(1893553445, 0),
+		Scopes:  []string{"x", "y"},
+	})
+	expectToken(t, ctx, "k2", "", "t2", apitokens.Metadata{})
+
+	_, err = apitokens.Load(context.Background(), apitokens.Command{
+		Path:   "sh",
+		Args:   []string{"-c", script, "helper"},
+		KeyIDs: []string{"k3"},
+	})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+}
+
+func TestExpiring(t *testing.T) {
+	soon := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	later := time.Now().Add(30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	t.Setenv("SOON", "a")
+	t.Setenv("SOON_EXPIRES", soon)
+	t.Setenv("LATER", "b")
+	t.Setenv("LATER_EXPIRES", later)
+	t.Setenv("NEVER", "c")
+	ctx, err := apitokens.Load(context.Background(),
+		apitokens.Env{Vars: map[string]string{"soon": "SOON", "later": "LATER", "never": "NEVER"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{"soon", "later", "never", "missing"}
+	if got, want := apitokens.Expiring(ctx, 24*time.Hour, ids...), []string{"soon"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := apitokens.Expiring(ctx, 60*24*time.Hour, ids...), []string{"soon", "later"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !apitokens.WarnExpiring(ctx, 24*time.Hour, ids...) {
+		t.Errorf("expected a warning")
+	}
+}
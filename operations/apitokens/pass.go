@@ -0,0 +1,117 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package apitokens
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// PassDir is a Loader that obtains tokens from a directory laid out as
+// for the pass password manager, i.e. a tree of gpg encrypted files, one
+// per token. The key ID for each token is the file's path relative to
+// Dir without the .gpg extension, e.g. "github/api". The first line of
+// each file is the token and subsequent lines may specify its metadata
+// as follows:
+//
+//	user: <user>          (login: and username: are also accepted)
+//	expires: <RFC 3339 time>
+//	scopes: <comma or space separated list>
+type PassDir struct {
+	Dir string
+	// Decrypt returns the decrypted contents of the named file, it defaults
+	// to running gpg --quiet --batch --decrypt. Files without a .gpg
+	// extension are read as is.
+	Decrypt func(ctx context.Context, filename string) ([]byte, error)
+}
+
+func gpgDecrypt(ctx context.Context, filename string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "gpg", "--quiet", "--batch", "--decrypt", filename)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gpg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// Load implements Loader.
+func (p PassDir) Load(ctx context.Context) ([]Entry, error) {
+	decrypt := p.Decrypt
+	if decrypt == nil {
+		decrypt = gpgDecrypt
+	}
+	var entries []Entry
+	err := filepath.WalkDir(p.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != p.Dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			// Skip .gpg-id and similar files.
+			return nil
+		}
+		var contents []byte
+		if filepath.Ext(path) == ".gpg" {
+			contents, err = decrypt(ctx, path)
+		} else {
+			contents, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		defer ClearToken(contents)
+		rel, err := filepath.Rel(p.Dir, path)
+		if err != nil {
+			return err
+		}
+		entry, err := parsePassEntry(filepath.ToSlash(strings.TrimSuffix(rel, ".gpg")), contents)
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+func parsePassEntry(id string, contents []byte) (Entry, error) {
+	entry := Entry{ID: id}
+	sc := bufio.NewScanner(bytes.NewReader(contents))
+	if sc.Scan() {
+		entry.Token = bytes.Clone(bytes.TrimRight(sc.Bytes(), "\r"))
+	}
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		switch strings.ToLower(strings.TrimSpace(k)) {
+		case "user", "login", "username":
+			entry.User = v
+		case "expires":
+			if err := entry.setExpires(v); err != nil {
+				return Entry{}, err
+			}
+		case "scopes":
+			entry.Scopes = parseScopes(v)
+		}
+	}
+	return entry, sc.Err()
+}
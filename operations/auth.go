@@ -7,6 +7,7 @@ package operations
 import (
 	"context"
 	"net/http"
+	"net/url"
 )
 
 // Auth represents an authorization mechanism.
@@ -15,3 +16,67 @@ type Auth interface {
 	// authorization information to the provided http.Request.
 	WithAuthorization(context.Context, *http.Request) error
 }
+
+// Invalidator is an optional interface that may be implemented by an
+// Auth to allow for requests to be re-authorized and retried when the
+// credentials used for them are rejected by the server, eg. because an
+// access token has expired or been revoked.
+type Invalidator interface {
+	// Invalidate is called when a request that was authorized using
+	// WithAuthorization is rejected with a 401 Unauthorized or 403
+	// Forbidden status code. It should discard any cached credentials
+	// and return true if the request should be re-authorized, via
+	// WithAuthorization, and retried. Requests are only re-authorized
+	// once.
+	Invalidate(ctx context.Context, resp *http.Response) bool
+}
+
+// Resigner is an optional interface that may be implemented by an Auth
+// whose authorization is only valid for a limited time, such as a
+// signature that covers the time at which the request was signed.
+// Requests authorized by such an Auth are restored to their state prior
+// to authorization and re-authorized before every attempt, including
+// retries following a backoff, rather than only before the first.
+type Resigner interface {
+	// ResignEachAttempt returns true if requests should be re-authorized
+	// before every attempt.
+	ResignEachAttempt() bool
+}
+
+// authState records the state of a request prior to it being authorized
+// so that it can be re-authorized.
+type authState struct {
+	header http.Header
+	url    url.URL
+}
+
+func newAuthState(req *http.Request) authState {
+	return authState{header: req.Header.Clone(), url: *req.URL}
+}
+
+func (as authState) reset(req *http.Request) {
+	req.Header = as.header.Clone()
+	u := as.url
+	req.URL = &u
+}
+
+// resignEachAttempt returns true if the Endpoint's Auth is a Resigner
+// that requires requests to be re-authorized before every attempt.
+func (ep *Endpoint[T]) resignEachAttempt() bool {
+	rs, ok := ep.auth.(Resigner)
+	return ok && rs.ResignEachAttempt()
+}
+
+// shouldReauthorize returns true if the response indicates that the
+// request's credentials were rejected and the Auth has invalidated
+// them.
+func (ep *Endpoint[T]) shouldReauthorize(ctx context.Context, resp *http.Response) bool {
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	inv, ok := ep.auth.(Invalidator)
+	if !ok {
+		return false
+	}
+	return inv.Invalidate(ctx, resp)
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HMAC is an implementation of operations.Auth that signs requests using
+// an HMAC of a canonical form of the request, keyed by the token of the
+// key identified by KeyID. The canonical request is the same as that
+// used by AWS Signature Version 4, see SigV4, namely:
+//
+//	method
+//	URI encoded path
+//	sorted, URI encoded, query parameters
+//	lowercased name:trimmed value for each signed header, sorted by name
+//	signed header names, lowercased, sorted and joined by ;
+//	hex encoded hash of the request body
+//
+// with each line separated by a newline. The signature is the hex encoded
+// HMAC of the canonical request.
+type HMAC struct {
+	KeyID string
+	// Header is the header in which the signature is sent, it defaults
+	// to Authorization.
+	Header string
+	// Hash is the hash function to use, it defaults to sha256.New.
+	Hash func() hash.Hash
+	// SignedHeaders are the headers to be included in the signature, they
+	// default to Host and Date. The Date header is set to the current time
+	// if it is to be signed and is not already set.
+	SignedHeaders []string
+	// Format returns the value of Header given the user associated with
+	// KeyID, the signed headers and the signature. The default format is:
+	//
+	//	HMAC Credential=<user>, SignedHeaders=<headers>, Signature=<signature>
+	Format func(user string, signedHeaders []string, signature string) string
+	// Now returns the current time, it defaults to time.Now.
+	Now func() time.Time
+}
+
+// WithAuthorization implements operations.Auth.
+func (h HMAC) WithAuthorization(ctx context.Context, req *http.Request) error {
+	token, err := tokenFromContext(ctx, h.KeyID, "hmac signing key")
+	if err != nil {
+		return err
+	}
+	defer token.Clear()
+	newHash := h.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	signed := h.SignedHeaders
+	if len(signed) == 0 {
+		signed = []string{"Host", "Date"}
+	}
+	for _, name := range signed {
+		if strings.EqualFold(name, "Date") && len(req.Header.Get("Date")) == 0 {
+			req.Header.Set("Date", now(h.Now).UTC().Format(http.TimeFormat))
+		}
+	}
+	payloadHash, err := hashBody(req, newHash)
+	if err != nil {
+		return err
+	}
+	canonical, names := canonicalRequest(req, signed, payloadHash)
+	mac := hmac.New(newHash, token.Value())
+	mac.Write([]byte(canonical))
+	signature := hex.EncodeToString(mac.Sum(nil))
+	format := h.Format
+	if format == nil {
+		format = defaultHMACFormat
+	}
+	header := h.Header
+	if len(header) == 0 {
+		header = "Authorization"
+	}
+	req.Header.Set(header, format(token.User, names, signature))
+	return nil
+}
+
+// ResignEachAttempt implements operations.Resigner since the signature
+// covers the Date header by default.
+func (h HMAC) ResignEachAttempt() bool {
+	return true
+}
+
+func defaultHMACFormat(user string, signedHeaders []string, signature string) string {
+	return fmt.Sprintf("HMAC Credential=%v, SignedHeaders=%v, Signature=%v",
+		user, strings.Join(signedHeaders, ";"), signature)
+}
+
+func now(fn func() time.Time) time.Time {
+	if fn == nil {
+		return time.Now()
+	}
+	return fn()
+}
+
+// hashBody returns the hex encoded hash of the request's body. The body
+// is read via GetBody if possible, otherwise it is read in its entirety
+// and replaced, along with GetBody, so that it may be re-read.
+func hashBody(req *http.Request, newHash func() hash.Hash) (string, error) {
+	h := newHash()
+	if req.Body == nil || req.Body == http.NoBody {
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	if req.GetBody == nil {
+		buf, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buf)), nil
+		}
+		req.Body, _ = req.GetBody()
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", fmt.Errorf("failed to obtain request body for signing: %w", err)
+	}
+	defer body.Close()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", fmt.Errorf("failed to read request body for signing: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// canonicalRequest returns the canonical form of req, as defined by AWS
+// Signature Version 4, and the lowercased, sorted, names of the signed
+// headers.
+func canonicalRequest(req *http.Request, signedHeaders []string, payloadHash string) (string, []string) {
+	names := make([]string, 0, len(signedHeaders))
+	for _, name := range signedHeaders {
+		names = append(names, strings.ToLower(name))
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	var out strings.Builder
+	method := req.Method
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	out.WriteString(method)
+	out.WriteByte('\n')
+	out.WriteString(canonicalPath(req.URL))
+	out.WriteByte('\n')
+	out.WriteString(canonicalQuery(req.URL.Query()))
+	out.WriteByte('\n')
+	for _, name := range names {
+		out.WriteString(name)
+		out.WriteByte(':')
+		out.WriteString(canonicalHeaderValue(req, name))
+		out.WriteByte('\n')
+	}
+	out.WriteByte('\n')
+	out.WriteString(strings.Join(names, ";"))
+	out.WriteByte('\n')
+	out.WriteString(payloadHash)
+	return out.String(), names
+}
+
+func canonicalPath(u *url.URL) string {
+	path := u.Path
+	if len(path) == 0 {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = uriEncode(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(q url.Values) string {
+	var pairs [][2]string
+	for k, vals := range q {
+		for _, v := range vals {
+			pairs = append(pairs, [2]string{uriEncode(k), uriEncode(v)})
+		}
+	}
+	slices.SortFunc(pairs, func(a, b [2]string) int {
+		if c := strings.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+		return strings.Compare(a[1], b[1])
+	})
+	encoded := make([]string, len(pairs))
+	for i, p := range pairs {
+		encoded[i] = p[0] + "=" + p[1]
+	}
+	return strings.Join(encoded, "&")
+}
+
+func canonicalHeaderValue(req *http.Request, name string) string {
+	if name == "host" {
+		if len(req.Host) > 0 {
+			return req.Host
+		}
+		return req.URL.Host
+	}
+	vals := slices.Clone(req.Header.Values(name))
+	for i, v := range vals {
+		vals[i] = strings.Join(strings.Fields(v), " ")
+	}
+	return strings.Join(vals, ",")
+}
+
+// uriEncode encodes s as per RFC 3986, escaping all but the unreserved
+// characters.
+func uriEncode(s string) string {
+	var out strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			out.WriteByte(c)
+		default:
+			fmt.Fprintf(&out, "%%%02X", c)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+// Package auth provides implementations of operations.Auth.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"cloudeng.io/webapi/operations/apitokens"
+	"golang.org/x/oauth2"
+)
+
+// OAuth2 is an implementation of operations.Auth, and operations.Invalidator,
+// that uses an oauth2.TokenSource to obtain access tokens. Tokens are
+// cached until they expire or are rejected by the server.
+type OAuth2 struct {
+	keyID  string
+	source oauth2.TokenSource
+
+	mu       sync.Mutex
+	token    *oauth2.Token
+	rejected string
+}
+
+// NewOAuth2 returns an OAuth2 that uses the oauth2.TokenSource stored
+// in the context, see apitokens.ContextWithOAuth, for the specified
+// key ID.
+func NewOAuth2(keyID string) *OAuth2 {
+	return &OAuth2{keyID: keyID}
+}
+
+// NewOAuth2FromSource returns an OAuth2 that uses the supplied
+// oauth2.TokenSource.
+func NewOAuth2FromSource(source oauth2.TokenSource) *OAuth2 {
+	return &OAuth2{source: source}
+}
+
+func (o *OAuth2) tokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	if o.source != nil {
+		return o.source, nil
+	}
+	return apitokens.OAuthFromContext(ctx, o.keyID)
+}
+
+// WithAuthorization implements operations.Auth.
+func (o *OAuth2) WithAuthorization(ctx context.Context, req *http.Request) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.token.Valid() {
+		source, err := o.tokenSource(ctx)
+		if err != nil {
+			return err
+		}
+		token, err := source.Token()
+		if err != nil {
+			return fmt.Errorf("failed to obtain oauth2 token for %q: %w", o.keyID, err)
+		}
+		if len(o.rejected) > 0 && token.AccessToken == o.rejected {
+			// The token source is caching the rejected token, eg. it is
+			// an oauth2.ReuseTokenSource, so retrying is pointless.
+			return fmt.Errorf("oauth2 token for %q was rejected and a new one could not be obtained", o.keyID)
+		}
+		o.token = token
+	}
+	o.token.SetAuthHeader(req)
+	return nil
+}
+
+// Invalidate implements operations.Invalidator. It discards the cached
+// token if it was rejected with a 401 Unauthorized status code.
+func (o *OAuth2) Invalidate(_ context.Context, resp *http.Response) bool {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.token == nil {
+		return true
+	}
+	if resp.Request != nil && resp.Request.Header.Get("Authorization") != o.token.Type()+" "+o.token.AccessToken {
+		// The request used a token that has since been replaced.
+		return true
+	}
+	o.rejected = o.token.AccessToken
+	o.token = nil
+	return true
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/operations/auth"
+	"cloudeng.io/webapi/webapitestutil"
+	"golang.org/x/oauth2"
+)
+
+type countingSource struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (cs *countingSource) Token() (*oauth2.Token, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.n++
+	return &oauth2.Token{AccessToken: fmt.Sprintf("tok-%v", cs.n), TokenType: "Bearer"}, nil
+}
+
+func newServer(valid string) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var seen []string
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer "+valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, "%q", "ok")
+	}))
+	return srv, &seen
+}
+
+func TestOAuth2Reauthorize(t *testing.T) {
+	ctx := context.Background()
+	srv, seen := newServer("tok-2")
+	defer srv.Close()
+
+	ep := operations.NewEndpoint[string](operations.WithAuth(auth.NewOAuth2FromSource(&countingSource{})))
+	got, _, _, err := ep.Get(ctx, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "ok" {
+		t.Errorf("got %v, want %v", got, "ok")
+	}
+	if got, want := strings.Join(*seen, ","), "Bearer tok-1,Bearer tok-2"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The new token is cached.
+	if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(*seen), 3; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestOAuth2Rejected(t *testing.T) {
+	ctx := context.Background()
+	srv, seen := newServer("tok-3")
+	defer srv.Close()
+
+	// Only a single re-authorization is attempted.
+	ep := operations.NewEndpoint[string](operations.WithAuth(auth.NewOAuth2FromSource(&countingSource{})))
+	_, _, _, err := ep.Get(ctx, srv.URL)
+	if !errors.Is(err, operations.ErrUnauthorized) {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+	if got, want := len(*seen), 2; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// A token source that returns the rejected token fails immediately.
+	static := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "static", TokenType: "Bearer"})
+	ep = operations.NewEndpoint[string](operations.WithAuth(auth.NewOAuth2FromSource(static)))
+	_, _, _, err = ep.Get(ctx, srv.URL)
+	if err == nil || !strings.Contains(err.Error(), "was rejected") {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package auth_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cloudeng.io/cmdutil/keys"
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/operations/apitokens"
+	"cloudeng.io/webapi/operations/auth"
+)
+
+func TestSigV4(t *testing.T) {
+	// Test cases from the AWS Signature Version 4 test suite.
+	ctx := apitokens.ContextWithKey(context.Background(),
+		keys.NewInfo("aws", "AKIDEXAMPLE", []byte("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")))
+	signer := auth.SigV4{
+		KeyID:   "aws",
+		Region:  "us-east-1",
+		Service: "service",
+		Now: func() time.Time {
+			return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+		},
+	}
+	for _, tc := range []struct {
+		url, signature string
+	}{
+		{"https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	} {
+		req := authorize(ctx, t, signer, tc.url)
+		want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + tc.signature
+		if got := req.Header.Get("Authorization"); got != want {
+			t.Errorf("%v: got %v, want %v", tc.url, got, want)
+		}
+		if got, want := req.Header.Get("X-Amz-Date"), "20150830T123600Z"; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	ctx := apitokens.ContextWithKey(context.Background(), keys.NewInfo("hmac", "client", []byte("key")))
+	signer := auth.HMAC{
+		KeyID: "hmac",
+		Now: func() time.Time {
+			return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://example.com/a b/c?z=1&a=%2F", strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.GetBody = nil
+	if err := signer.WithAuthorization(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	bodyHash := sha256.Sum256([]byte("body"))
+	canonical := strings.Join([]string{
+		"POST",
+		"/a%20b/c",
+		"a=%2F&z=1",
+		"date:Fri, 02 Jan 2026 03:04:05 GMT",
+		"host:example.com",
+		"",
+		"date;host",
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte(canonical))
+	want := "HMAC Credential=client, SignedHeaders=date;host, Signature=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The body must still be readable.
+	buf := make([]byte, 10)
+	n, _ := req.Body.Read(buf)
+	if got, want := string(buf[:n]), "body"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestResignRetries(t *testing.T) {
+	ctx := apitokens.ContextWithKey(context.Background(), keys.NewInfo("key", "user", []byte("secret")))
+	clock := func() func() time.Time {
+		now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		return func() time.Time {
+			now = now.Add(10 * time.Minute)
+			return now
+		}
+	}
+	for _, tc := range []struct {
+		auth       operations.Auth
+		dateHeader string
+	}{
+		{auth.SigV4{KeyID: "key", Region: "us-east-1", Service: "s3", Now: clock()}, "X-Amz-Date"},
+		{auth.HMAC{KeyID: "key", Now: clock()}, "Date"},
+	} {
+		var dates, signatures []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dates = append(dates, r.Header.Get(tc.dateHeader))
+			signatures = append(signatures, r.Header.Get("Authorization"))
+			if len(dates) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_ = json.NewEncoder(w).Encode("ok")
+		}))
+		rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 2))
+		ep := operations.NewEndpoint[string](
+			operations.WithAuth(tc.auth),
+			operations.WithRateController(rc, http.StatusServiceUnavailable))
+		if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+		srv.Close()
+		if got, want := len(dates), 2; got != want {
+			t.Fatalf("%T: got %v, want %v", tc.auth, got, want)
+		}
+		if dates[0] == dates[1] || len(dates[1]) == 0 {
+			t.Errorf("%T: retry was not re-dated: %q", tc.auth, dates)
+		}
+		if signatures[0] == signatures[1] || len(signatures[1]) == 0 {
+			t.Errorf("%T: retry was not re-signed: %q", tc.auth, signatures)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4DateFormat = "20060102T150405Z"
+)
+
+// SigV4 is an implementation of operations.Auth that signs requests using
+// AWS Signature Version 4. The key identified by KeyID provides the access
+// key ID, as its user, and the secret access key, as its token.
+type SigV4 struct {
+	KeyID   string
+	Region  string
+	Service string
+	// SessionTokenKeyID, if set, identifies a key whose token is a session
+	// token for temporary credentials, it is sent, and signed, as the
+	// X-Amz-Security-Token header.
+	SessionTokenKeyID string
+	// SignedHeaders are headers to be signed in addition to Host,
+	// X-Amz-Date and, if set, X-Amz-Security-Token and
+	// X-Amz-Content-Sha256.
+	SignedHeaders []string
+	// ContentSHA256 specifies that the X-Amz-Content-Sha256 header is to
+	// be set to the hash of the request body, as required by S3.
+	ContentSHA256 bool
+	// Now returns the current time, it defaults to time.Now.
+	Now func() time.Time
+}
+
+// WithAuthorization implements operations.Auth.
+func (s SigV4) WithAuthorization(ctx context.Context, req *http.Request) error {
+	token, err := tokenFromContext(ctx, s.KeyID, "aws sigv4 credentials")
+	if err != nil {
+		return err
+	}
+	defer token.Clear()
+	signed := append([]string{"Host", "X-Amz-Date"}, s.SignedHeaders...)
+	if len(s.SessionTokenKeyID) > 0 {
+		session, err := tokenFromContext(ctx, s.SessionTokenKeyID, "aws session token")
+		if err != nil {
+			return err
+		}
+		req.Header.Set("X-Amz-Security-Token", string(session.Value()))
+		session.Clear()
+		signed = append(signed, "X-Amz-Security-Token")
+	}
+	payloadHash, err := hashBody(req, sha256.New)
+	if err != nil {
+		return err
+	}
+	if s.ContentSHA256 {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+		signed = append(signed, "X-Amz-Content-Sha256")
+	}
+	t := now(s.Now).UTC()
+	req.Header.Set("X-Amz-Date", t.Format(sigV4DateFormat))
+
+	canonical, names := canonicalRequest(req, signed, payloadHash)
+	day := t.Format("20060102")
+	scope := strings.Join([]string{day, s.Region, s.Service, "aws4_request"}, "/")
+	digest := sha256.Sum256([]byte(canonical))
+	toSign := strings.Join([]string{sigV4Algorithm, t.Format(sigV4DateFormat), scope, hex.EncodeToString(digest[:])}, "\n")
+
+	key := append([]byte("AWS4"), token.Value()...)
+	for _, part := range []string{day, s.Region, s.Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("%v Credential=%v/%v, SignedHeaders=%v, Signature=%v",
+		sigV4Algorithm, token.User, scope, strings.Join(names, ";"), signature))
+	return nil
+}
+
+// ResignEachAttempt implements operations.Resigner since the signature
+// covers the X-Amz-Date header and is only valid for a limited time.
+func (s SigV4) ResignEachAttempt() bool {
+	return true
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+
+	"cloudeng.io/cmdutil/keys"
+	"cloudeng.io/webapi/operations/apitokens"
+)
+
+func tokenFromContext(ctx context.Context, keyID, service string) (*keys.Token, error) {
+	token, ok := apitokens.TokenFromContext(ctx, keyID)
+	if !ok {
+		return nil, apitokens.NewErrNotFound(keyID, service)
+	}
+	return token, nil
+}
+
+// Bearer is an implementation of operations.Auth that sends the API token
+// identified by KeyID as a bearer token in the Authorization header.
+type Bearer struct {
+	KeyID string
+}
+
+// WithAuthorization implements operations.Auth.
+func (b Bearer) WithAuthorization(ctx context.Context, req *http.Request) error {
+	token, err := tokenFromContext(ctx, b.KeyID, "bearer token")
+	if err != nil {
+		return err
+	}
+	defer token.Clear()
+	req.Header.Set("Authorization", "Bearer "+string(token.Value()))
+	return nil
+}
+
+// Basic is an implementation of operations.Auth that uses HTTP basic
+// authentication with the user and token of the key identified by KeyID
+// as the user name and password. If the key has no user then the token
+// is used as the user name with an empty password, as expected by
+// APIs such as Benchling's and Stripe's.
+type Basic struct {
+	KeyID string
+}
+
+// WithAuthorization implements operations.Auth.
+func (b Basic) WithAuthorization(ctx context.Context, req *http.Request) error {
+	token, err := tokenFromContext(ctx, b.KeyID, "basic auth")
+	if err != nil {
+		return err
+	}
+	var creds []byte
+	if len(token.User) > 0 {
+		creds = append([]byte(token.User+":"), token.Value()...)
+	} else {
+		creds = append(append(creds, token.Value()...), ':')
+	}
+	token.Clear()
+	defer apitokens.ClearToken(creds)
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString(creds))
+	return nil
+}
+
+// APIKeyHeader is an implementation of operations.Auth that sends the API
+// token identified by KeyID in the specified header, optionally preceded by
+// Prefix, for example:
+//
+//	auth.APIKeyHeader{KeyID: "github", Header: "Authorization", Prefix: "token "}
+//	auth.APIKeyHeader{KeyID: "service", Header: "X-API-Key"}
+type APIKeyHeader struct {
+	KeyID  string
+	Header string
+	Prefix string
+}
+
+// WithAuthorization implements operations.Auth.
+func (a APIKeyHeader) WithAuthorization(ctx context.Context, req *http.Request) error {
+	token, err := tokenFromContext(ctx, a.KeyID, "api key header "+a.Header)
+	if err != nil {
+		return err
+	}
+	defer token.Clear()
+	req.Header.Set(a.Header, a.Prefix+string(token.Value()))
+	return nil
+}
+
+// APIKeyQuery is an implementation of operations.Auth that sends the API
+// token identified by KeyID as the value of the specified URL query
+// parameter. The original URL is restored should the request need to be
+// re-authorized. Note that the key will appear in the request's URL and
+// hence, unless Param is one of operations.DefaultQueryRedactions, it
+// should be passed to operations.WithRedactions and to any
+// operations.Logging middleware so that it is redacted.
+type APIKeyQuery struct {
+	KeyID string
+	Param string
+}
+
+// WithAuthorization implements operations.Auth.
+func (a APIKeyQuery) WithAuthorization(ctx context.Context, req *http.Request) error {
+	token, err := tokenFromContext(ctx, a.KeyID, "api key parameter "+a.Param)
+	if err != nil {
+		return err
+	}
+	defer token.Clear()
+	u := *req.URL
+	q := u.Query()
+	q.Set(a.Param, string(token.Value()))
+	u.RawQuery = q.Encode()
+	req.URL = &u
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cloudeng.io/cmdutil/keys"
+	"cloudeng.io/logging/ctxlog"
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/operations/apitokens"
+	"cloudeng.io/webapi/operations/auth"
+)
+
+func authorize(ctx context.Context, t *testing.T, a operations.Auth, u string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.WithAuthorization(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestTokenAuth(t *testing.T) {
+	ctx := context.Background()
+	ctx = apitokens.ContextWithKey(ctx, keys.NewInfo("tok", "", []byte("secret")))
+	ctx = apitokens.ContextWithKey(ctx, keys.NewInfo("user", "alice", []byte("pw")))
+
+	for _, tc := range []struct {
+		auth   operations.Auth
+		header string
+		want   string
+	}{
+		{auth.Bearer{KeyID: "tok"}, "Authorization", "Bearer secret"},
+		{auth.Basic{KeyID: "tok"}, "Authorization", "Basic c2VjcmV0Og=="},
+		{auth.Basic{KeyID: "user"}, "Authorization", "Basic YWxpY2U6cHc="},
+		{auth.APIKeyHeader{KeyID: "tok", Header: "X-API-Key"}, "X-API-Key", "secret"},
+		{auth.APIKeyHeader{KeyID: "tok", Header: "Authorization", Prefix: "token "}, "Authorization", "token secret"},
+	} {
+		req := authorize(ctx, t, tc.auth, "https://example.com/path")
+		if got, want := req.Header.Get(tc.header), tc.want; got != want {
+			t.Errorf("%T: got %v, want %v", tc.auth, got, want)
+		}
+	}
+
+	req := authorize(ctx, t, auth.APIKeyQuery{KeyID: "tok", Param: "api_key"}, "https://example.com/path?q=a")
+	if got, want := req.URL.String(), "https://example.com/path?api_key=secret&q=a"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	err := auth.Bearer{KeyID: "missing"}.WithAuthorization(ctx, req)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+}
+
+type reauthorizingAPIKeyQuery struct {
+	auth.APIKeyQuery
+}
+
+func (reauthorizingAPIKeyQuery) Invalidate(context.Context, *http.Response) bool {
+	return true
+}
+
+func TestAPIKeyQueryLogging(t *testing.T) {
+	var logs bytes.Buffer
+	ctx := ctxlog.WithLogger(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)))
+	ctx = apitokens.ContextWithKey(ctx, keys.NewInfo("tok", "", []byte("secret")))
+	count := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		count++
+		switch count {
+		case 1:
+			w.WriteHeader(http.StatusUnauthorized)
+		case 2:
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_ = json.NewEncoder(w).Encode("ok")
+		}
+	}))
+	defer srv.Close()
+
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 2))
+	ep := operations.NewEndpoint[string](
+		operations.WithAuth(reauthorizingAPIKeyQuery{auth.APIKeyQuery{KeyID: "tok", Param: "key"}}),
+		operations.WithRedactions("key"),
+		operations.WithRateController(rc, http.StatusTooManyRequests))
+	if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"re-authorizing request", "server specified backoff", "application backoff"} {
+		if !strings.Contains(logs.String(), msg) {
+			t.Errorf("missing log message: %q", msg)
+		}
+	}
+	if strings.Contains(logs.String(), "secret") {
+		t.Errorf("api key not redacted: %s", logs.String())
+	}
+}
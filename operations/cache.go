@@ -0,0 +1,217 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"cloudeng.io/file/content"
+	"cloudeng.io/logging/ctxlog"
+)
+
+// CachedResponse represents a response stored in a ResponseCache.
+type CachedResponse struct {
+	ETag         string   `json:"etag,omitempty"`
+	LastModified string   `json:"last_modified,omitempty"`
+	Encoding     Encoding `json:"encoding"`
+	Body         []byte   `json:"body"`
+}
+
+// ResponseCache is used to store the bodies of responses that carry an ETag
+// or Last-Modified header so that subsequent requests for the same URL can
+// be made conditional (using If-None-Match and If-Modified-Since) and a
+// 304 Not Modified response satisfied from the cache.
+type ResponseCache interface {
+	// Get returns the cached response for key, if any.
+	Get(ctx context.Context, key string) (CachedResponse, bool, error)
+	// Put stores the response for key.
+	Put(ctx context.Context, key string, cr CachedResponse) error
+}
+
+// NewMemoryResponseCache returns a ResponseCache that stores at most
+// maxEntries responses in memory, evicting the least recently used
+// response when full. If maxEntries is zero or negative the cache is
+// unbounded: no responses are ever evicted and its memory use grows
+// with every distinct response stored, so NewFSResponseCache should be
+// used for long running crawls.
+func NewMemoryResponseCache(maxEntries int) ResponseCache {
+	return &memoryCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+type memoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List // of *memoryCacheEntry, most recently used first.
+}
+
+type memoryCacheEntry struct {
+	key string
+	cr  CachedResponse
+}
+
+func (mc *memoryCache) Get(_ context.Context, key string) (CachedResponse, bool, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	e, ok := mc.entries[key]
+	if !ok {
+		return CachedResponse{}, false, nil
+	}
+	mc.lru.MoveToFront(e)
+	return e.Value.(*memoryCacheEntry).cr, true, nil
+}
+
+func (mc *memoryCache) Put(_ context.Context, key string, cr CachedResponse) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if e, ok := mc.entries[key]; ok {
+		e.Value.(*memoryCacheEntry).cr = cr
+		mc.lru.MoveToFront(e)
+		return nil
+	}
+	mc.entries[key] = mc.lru.PushFront(&memoryCacheEntry{key: key, cr: cr})
+	if mc.maxEntries > 0 && mc.lru.Len() > mc.maxEntries {
+		oldest := mc.lru.Back()
+		mc.lru.Remove(oldest)
+		delete(mc.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+	return nil
+}
+
+// NewFSResponseCache returns a ResponseCache that stores responses as
+// JSON files in the specified directory. The file name used for each
+// response is the hex encoded SHA1 hash of its key.
+func NewFSResponseCache(fs content.FS, root string) ResponseCache {
+	return &fsCache{fs: fs, root: root}
+}
+
+type fsCache struct {
+	fs       content.FS
+	root     string
+	mu       sync.Mutex
+	prepared bool
+}
+
+func (fc *fsCache) filename(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return fc.fs.Join(fc.root, hex.EncodeToString(sum[:]))
+}
+
+func (fc *fsCache) Get(ctx context.Context, key string) (CachedResponse, bool, error) {
+	var cr CachedResponse
+	buf, err := fc.fs.ReadFileCtx(ctx, fc.filename(key))
+	if err != nil {
+		if fc.fs.IsNotExist(err) {
+			return cr, false, nil
+		}
+		return cr, false, err
+	}
+	if err := json.Unmarshal(buf, &cr); err != nil {
+		return cr, false, err
+	}
+	return cr, true, nil
+}
+
+func (fc *fsCache) Put(ctx context.Context, key string, cr CachedResponse) error {
+	if err := fc.ensurePrefix(ctx); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(cr)
+	if err != nil {
+		return err
+	}
+	return fc.fs.WriteFileCtx(ctx, fc.filename(key), buf, 0600)
+}
+
+func (fc *fsCache) ensurePrefix(ctx context.Context) error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.prepared {
+		return nil
+	}
+	if err := fc.fs.EnsurePrefix(ctx, fc.root, 0700); err != nil {
+		return err
+	}
+	fc.prepared = true
+	return nil
+}
+
+// cacheKey returns the key to use for caching the response to req,
+// only GET requests are cached.
+func cacheKey(req *http.Request) (string, bool) {
+	if req.Method != "" && req.Method != http.MethodGet {
+		return "", false
+	}
+	return req.URL.String(), true
+}
+
+// conditional looks up req in the cache and if found makes the request
+// conditional on the cached response having changed.
+func (ep *Endpoint[T]) conditional(ctx context.Context, req *http.Request) (CachedResponse, bool) {
+	key, ok := cacheKey(req)
+	if !ok {
+		return CachedResponse{}, false
+	}
+	cr, ok, err := ep.responseCache.Get(ctx, key)
+	if err != nil {
+		ctxlog.Info(ctx, "response cache lookup failed", slog.Group("req", "url", ep.redactedURL(req.URL), "err", err))
+		return cr, false
+	}
+	if !ok {
+		return cr, false
+	}
+	if len(cr.ETag) > 0 && len(req.Header.Get("If-None-Match")) == 0 {
+		req.Header.Set("If-None-Match", cr.ETag)
+	}
+	if len(cr.LastModified) > 0 && len(req.Header.Get("If-Modified-Since")) == 0 {
+		req.Header.Set("If-Modified-Since", cr.LastModified)
+	}
+	return cr, true
+}
+
+// cacheResult stores the body of a successful response in the cache if
+// the response carries an ETag or Last-Modified header.
+func (ep *Endpoint[T]) cacheResult(ctx context.Context, req *http.Request, res result[T]) {
+	key, ok := cacheKey(req)
+	if !ok || res.resp == nil || res.resp.StatusCode != http.StatusOK || res.body == nil {
+		return
+	}
+	cr := CachedResponse{
+		ETag:         res.resp.Header.Get("ETag"),
+		LastModified: res.resp.Header.Get("Last-Modified"),
+		Encoding:     res.encoding,
+		Body:         res.body,
+	}
+	if len(cr.ETag) == 0 && len(cr.LastModified) == 0 {
+		return
+	}
+	if err := ep.responseCache.Put(ctx, key, cr); err != nil {
+		ctxlog.Info(ctx, "response cache update failed", slog.Group("req", "url", ep.redactedURL(req.URL), "err", err))
+	}
+}
+
+// fromCache returns the result for a 304 Not Modified response using
+// the cached response.
+func (ep *Endpoint[T]) fromCache(resp *http.Response, cr CachedResponse, steps int) (result[T], error) {
+	resp.Body.Close()
+	res := result[T]{resp: resp, body: cr.Body, encoding: cr.Encoding, cacheHit: true}
+	unmarshal := ep.unmarshal
+	if ep.autoEncoding {
+		unmarshal = cr.Encoding.Unmarshal
+	}
+	err := unmarshal(cr.Body, &res.value)
+	return res, handleError(err, resp.Status, http.StatusOK, steps)
+}
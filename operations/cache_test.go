@@ -0,0 +1,200 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cloudeng.io/file/localfs"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func TestResponseCache(t *testing.T) {
+	ctx := context.Background()
+	version := "v1"
+	full, notModified := 0, 0
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + version + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(example{version, full})
+	}))
+	defer srv.Close()
+
+	client := operations.NewEndpoint[example](
+		operations.WithResponseCache(operations.NewMemoryResponseCache(10)))
+
+	get := func() (example, operations.Response) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		val, resp, err := client.Do(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return val, resp
+	}
+
+	val, resp := get()
+	if got, want := val, (example{"v1", 1}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if resp.CacheHit {
+		t.Errorf("unexpected cache hit")
+	}
+
+	val, resp = get()
+	if got, want := val, (example{"v1", 1}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !resp.CacheHit || len(resp.Bytes) == 0 {
+		t.Errorf("expected a cache hit with a body")
+	}
+	if got, want := resp.StatusCode, http.StatusNotModified; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	version = "v2"
+	val, resp = get()
+	if got, want := val, (example{"v2", 2}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if resp.CacheHit {
+		t.Errorf("unexpected cache hit")
+	}
+	if got, want := full, 2; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := notModified, 1; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMemoryResponseCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := operations.NewMemoryResponseCache(2)
+	for _, key := range []string{"a", "b"} {
+		if err := cache.Put(ctx, key, operations.CachedResponse{ETag: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Use a so that b is the least recently used.
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Errorf("a should be cached")
+	}
+	if err := cache.Put(ctx, "c", operations.CachedResponse{ETag: "c"}); err != nil {
+		t.Fatal(err)
+	}
+	for key, cached := range map[string]bool{"a": true, "b": false, "c": true} {
+		cr, ok, err := cache.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ok, cached; got != want {
+			t.Errorf("%v: got %v, want %v", key, got, want)
+		}
+		if ok && cr.ETag != key {
+			t.Errorf("got %v, want %v", cr.ETag, key)
+		}
+	}
+}
+
+func TestFSResponseCache(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "cache")
+	cache := operations.NewFSResponseCache(localfs.New(), root)
+
+	// Round trip.
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("unexpected entry or error: %v, %v", ok, err)
+	}
+	in := operations.CachedResponse{ETag: `"e"`, LastModified: "lm", Encoding: operations.JSONEncoding, Body: []byte(`{}`)}
+	if err := cache.Put(ctx, "key", in); err != nil {
+		t.Fatal(err)
+	}
+	out, ok, err := cache.Get(ctx, "key")
+	if err != nil || !ok {
+		t.Fatalf("missing entry or error: %v, %v", ok, err)
+	}
+	if got, want := out.ETag+out.LastModified+string(out.Body), in.ETag+in.LastModified+string(in.Body); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Revalidation using ETag and Last-Modified.
+	lastModified := "Mon, 02 Jan 2006 15:04:05 GMT"
+	full, notModified := 0, 0
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/etag" && r.Header.Get("If-None-Match") == `"v1"`,
+			r.URL.Path == "/modified" && r.Header.Get("If-Modified-Since") == lastModified:
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		case r.URL.Path == "/etag":
+			w.Header().Set("ETag", `"v1"`)
+		default:
+			w.Header().Set("Last-Modified", lastModified)
+		}
+		full++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(example{r.URL.Path, full})
+	}))
+	defer srv.Close()
+
+	client := operations.NewEndpoint[example](operations.WithResponseCache(cache))
+	get := func(path string) (example, operations.Response) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		val, resp, err := client.Do(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return val, resp
+	}
+	for i, path := range []string{"/etag", "/modified"} {
+		for j := range 2 {
+			val, resp := get(path)
+			if got, want := val, (example{path, i + 1}); got != want {
+				t.Errorf("%v: got %v, want %v", path, got, want)
+			}
+			if got, want := resp.CacheHit, j == 1; got != want {
+				t.Errorf("%v: got %v, want %v", path, got, want)
+			}
+		}
+	}
+	if got, want := notModified, 2; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// A corrupt entry is treated as a cache miss and replaced.
+	sum := sha1.Sum([]byte(srv.URL + "/etag"))
+	if err := os.WriteFile(filepath.Join(root, hex.EncodeToString(sum[:])), []byte("corrupt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := cache.Get(ctx, srv.URL+"/etag"); err == nil {
+		t.Errorf("expected an error for a corrupt entry")
+	}
+	val, resp := get("/etag")
+	if got, want := val, (example{"/etag", 3}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if resp.CacheHit {
+		t.Errorf("unexpected cache hit")
+	}
+	if _, resp = get("/etag"); !resp.CacheHit {
+		t.Errorf("expected a cache hit")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cloudeng.io/file/checkpoint"
+	"cloudeng.io/file/content"
+	"cloudeng.io/logging/ctxlog"
+)
+
+// WithCheckpoint specifies a checkpoint.Operation that RunCrawl uses to
+// persist the progress of a crawl. Once the handler has successfully
+// processed a batch of objects, the last non-empty Response.Checkpoint
+// in that batch is saved using op. Since the checkpoint is only saved
+// after the handler returns, handlers must have durably stored the
+// objects (eg. flushed any buffered writes) before returning. A failure
+// to save a checkpoint terminates the crawl. Use LoadCheckpoint to
+// obtain the checkpoint when resuming a crawl.
+func WithCheckpoint(op checkpoint.Operation) CrawlOption {
+	return func(o *crawlOptions) {
+		o.checkpoint = op
+	}
+}
+
+// LoadCheckpoint reads the latest checkpoint, if any, from op and decodes
+// it, as JSON, into v. It returns false if op is nil or there is no
+// checkpoint, in which case v is left unchanged.
+func LoadCheckpoint(ctx context.Context, op checkpoint.Operation, v any) (bool, error) {
+	if op == nil {
+		return false, nil
+	}
+	buf, err := op.Latest(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(buf) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(buf, v); err != nil {
+		return false, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	return true, nil
+}
+
+// SaveCheckpoint encodes v as JSON and saves it as a new checkpoint
+// using op.
+func SaveCheckpoint(ctx context.Context, op checkpoint.Operation, v any) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	_, err = op.Checkpoint(ctx, "", buf)
+	return err
+}
+
+// latestCheckpoint returns the last non-empty checkpoint in objs.
+func latestCheckpoint[EndpointT any](objs []content.Object[EndpointT, Response]) []byte {
+	for i := len(objs) - 1; i >= 0; i-- {
+		if cp := objs[i].Response.Checkpoint; len(cp) > 0 {
+			return cp
+		}
+	}
+	return nil
+}
+
+func saveLatestCheckpoint[EndpointT any](ctx context.Context, op checkpoint.Operation, objs []content.Object[EndpointT, Response]) error {
+	if op == nil {
+		return nil
+	}
+	cp := latestCheckpoint(objs)
+	if cp == nil {
+		return nil
+	}
+	id, err := op.Checkpoint(ctx, "", cp)
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	ctxlog.Debug(ctx, "saved crawl checkpoint", "id", id)
+	return nil
+}
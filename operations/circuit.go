@@ -0,0 +1,192 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped in an *Error, when a request is
+// rejected because the circuit breaker for its host is open. Use
+// errors.As with a *CircuitOpenError to obtain more details.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitOpenError is the error returned when a request is rejected
+// because the circuit breaker for its host is open.
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time
+}
+
+// Error implements error.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v: %v: until %v", ErrCircuitOpen, e.Host, e.Until.Format(time.RFC3339))
+}
+
+// Is supports errors.Is(err, ErrCircuitOpen).
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitState represents the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed is the normal state where all requests are allowed.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen is the state where all requests are rejected.
+	CircuitOpen
+	// CircuitHalfOpen is the state, entered once the cool down period
+	// has expired, where a limited number of probe requests are allowed.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("unknown circuit state: %d", int(s))
+}
+
+// CircuitBreakerConfig represents the configuration of a circuit breaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures (network
+	// errors or 5xx status codes) that open the circuit, the default is 5.
+	FailureThreshold int
+	// CoolDown is the period for which the circuit remains open before
+	// allowing probe requests, the default is one minute.
+	CoolDown time.Duration
+	// HalfOpenProbes is the number of probe requests allowed, and that must
+	// succeed to close the circuit, when it is half-open. The default is 1.
+	HalfOpenProbes int
+}
+
+type circuit struct {
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+}
+
+// CircuitBreakers maintains a circuit breaker per host. A single instance
+// should be shared by all Endpoints (and hence Scanners and Fetchers) that
+// access the same API so that they all stop making requests to that API
+// when it is experiencing an outage.
+type CircuitBreakers struct {
+	cfg   CircuitBreakerConfig
+	mu    sync.Mutex
+	hosts map[string]*circuit
+	now   func() time.Time
+}
+
+// NewCircuitBreakers returns a new set of per-host circuit breakers.
+func NewCircuitBreakers(cfg CircuitBreakerConfig) *CircuitBreakers {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = time.Minute
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	return &CircuitBreakers{
+		cfg:   cfg,
+		hosts: map[string]*circuit{},
+		now:   time.Now,
+	}
+}
+
+// State returns the current state of the circuit breaker for host.
+func (cb *CircuitBreakers) State(host string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	c := cb.hosts[host]
+	if c == nil {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && cb.now().Sub(c.openedAt) >= cb.cfg.CoolDown {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// allow returns a *CircuitOpenError if a request to host is not allowed.
+// Every call that returns nil must be followed by a call to record.
+func (cb *CircuitBreakers) allow(host string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	c := cb.hosts[host]
+	if c == nil {
+		c = &circuit{}
+		cb.hosts[host] = c
+	}
+	now := cb.now()
+	switch c.state {
+	case CircuitClosed:
+		return nil
+	case CircuitOpen:
+		if until := c.openedAt.Add(cb.cfg.CoolDown); now.Before(until) {
+			return &CircuitOpenError{Host: host, Until: until}
+		}
+		c.state = CircuitHalfOpen
+		c.probes, c.successes = 0, 0
+	}
+	if c.probes >= cb.cfg.HalfOpenProbes {
+		return &CircuitOpenError{Host: host, Until: now}
+	}
+	c.probes++
+	return nil
+}
+
+// record records the outcome of a request that was allowed. Errors that
+// result from the request's context being canceled are not considered
+// to be failures.
+func (cb *CircuitBreakers) record(host string, err error, statusCode int) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	c := cb.hosts[host]
+	if c == nil {
+		return
+	}
+	neutral := errors.Is(err, context.Canceled)
+	failed := !neutral && (err != nil || statusCode >= 500)
+	switch c.state {
+	case CircuitClosed:
+		switch {
+		case failed:
+			c.failures++
+			if c.failures >= cb.cfg.FailureThreshold {
+				c.state = CircuitOpen
+				c.openedAt = cb.now()
+			}
+		case !neutral:
+			c.failures = 0
+		}
+	case CircuitHalfOpen:
+		c.probes--
+		switch {
+		case failed:
+			c.state = CircuitOpen
+			c.openedAt = cb.now()
+		case !neutral:
+			c.successes++
+			if c.successes >= cb.cfg.HalfOpenProbes {
+				c.state = CircuitClosed
+				c.failures = 0
+			}
+		}
+	}
+}
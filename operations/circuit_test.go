@@ -0,0 +1,132 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	var healthy atomic.Bool
+	var count atomic.Int64
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		count.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(1)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	cb := operations.NewCircuitBreakers(operations.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		CoolDown:         50 * time.Millisecond,
+	})
+	ep1 := operations.NewEndpoint[int](operations.WithCircuitBreakers(cb))
+	ep2 := operations.NewEndpoint[int](operations.WithCircuitBreakers(cb))
+
+	for i := 0; i < 2; i++ {
+		if _, _, _, err := ep1.Get(ctx, srv.URL); err == nil || errors.Is(err, operations.ErrCircuitOpen) {
+			t.Fatalf("unexpected or missing error: %v", err)
+		}
+	}
+	if got, want := cb.State(u.Host), operations.CircuitOpen; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The circuit is shared by all endpoints for the same host.
+	_, _, _, err := ep2.Get(ctx, srv.URL)
+	if !errors.Is(err, operations.ErrCircuitOpen) {
+		t.Fatalf("unexpected or missing error: %v", err)
+	}
+	var coerr *operations.CircuitOpenError
+	if !errors.As(err, &coerr) || coerr.Host != u.Host {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got, want := count.Load(), int64(2); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if got, want := cb.State(u.Host), operations.CircuitHalfOpen; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// A failed probe re-opens the circuit.
+	if _, _, _, err := ep1.Get(ctx, srv.URL); err == nil || errors.Is(err, operations.ErrCircuitOpen) {
+		t.Fatalf("unexpected or missing error: %v", err)
+	}
+	if got, want := cb.State(u.Host), operations.CircuitOpen; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	healthy.Store(true)
+	if _, _, _, err := ep2.Get(ctx, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cb.State(u.Host), operations.CircuitClosed; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCircuitBreakerCanceledProbe(t *testing.T) {
+	var healthy atomic.Bool
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !healthy.Load() {
+			// Exhaust the quota so that the adaptive rate controller
+			// pauses all subsequent requests.
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "10")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(1)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	cb := operations.NewCircuitBreakers(operations.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		CoolDown:         50 * time.Millisecond,
+	})
+	arc := operations.NewAdaptiveRateController(operations.AdaptiveRateConfig{MaxRate: 1000})
+	ep := operations.NewEndpoint[int](operations.WithCircuitBreakers(cb), operations.WithAdaptiveRateControl(arc))
+	if _, _, _, err := ep.Get(context.Background(), srv.URL); err == nil {
+		t.Fatal("expected an error")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if got, want := cb.State(u.Host), operations.CircuitHalfOpen; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The probe is canceled whilst waiting on the adaptive rate controller.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, _, err := ep.Get(ctx, srv.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected or missing error: %v", err)
+	}
+
+	// The probe must still be available to other requests.
+	healthy.Store(true)
+	if _, _, _, err := operations.NewEndpoint[int](operations.WithCircuitBreakers(cb)).Get(context.Background(), srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cb.State(u.Host), operations.CircuitClosed; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Codec represents an HTTP content coding, such as gzip, that can be
+// used to decode compressed response bodies.
+type Codec interface {
+	// Name returns the content coding's name as used in the
+	// Accept-Encoding and Content-Encoding headers, eg. "gzip", "br"
+	// or "zstd".
+	Name() string
+	// NewReader returns a reader that decodes the data read from rd.
+	NewReader(rd io.Reader) (io.ReadCloser, error)
+}
+
+type codec struct {
+	name      string
+	newReader func(io.Reader) (io.ReadCloser, error)
+}
+
+func (c codec) Name() string {
+	return c.name
+}
+
+func (c codec) NewReader(rd io.Reader) (io.ReadCloser, error) {
+	return c.newReader(rd)
+}
+
+// NewCodec returns a Codec for the named content coding that uses
+// newReader to create decoders. It allows for codecs that are not
+// provided by the standard library, such as brotli and zstd, to be
+// used, for example:
+//
+//	brotli := operations.NewCodec("br", func(rd io.Reader) (io.ReadCloser, error) {
+//		return io.NopCloser(brotli.NewReader(rd)), nil
+//	})
+//	zstd := operations.NewCodec("zstd", func(rd io.Reader) (io.ReadCloser, error) {
+//		dec, err := zstd.NewReader(rd)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return dec.IOReadCloser(), nil
+//	})
+//	ep := operations.NewEndpoint[T](operations.WithCompression(zstd, brotli, operations.GzipCodec))
+func NewCodec(name string, newReader func(io.Reader) (io.ReadCloser, error)) Codec {
+	return codec{name: name, newReader: newReader}
+}
+
+var (
+	// GzipCodec is the Codec for the gzip content coding.
+	GzipCodec = NewCodec("gzip", func(rd io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(rd)
+	})
+
+	// DeflateCodec is the Codec for the deflate content coding. Although
+	// deflate is defined as zlib wrapped data, some servers send raw
+	// deflate data and hence both are accepted.
+	DeflateCodec = NewCodec("deflate", newDeflateReader)
+)
+
+func newDeflateReader(rd io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(rd)
+	hdr, err := br.Peek(2)
+	if err == nil && isZlibHeader(hdr) {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// isZlibHeader returns true if hdr is a valid RFC 1950 header for
+// deflate compressed data.
+func isZlibHeader(hdr []byte) bool {
+	return hdr[0]&0x0f == 8 && (uint16(hdr[0])<<8|uint16(hdr[1]))%31 == 0
+}
+
+// WithCompression specifies the content codings, in order of preference,
+// that are to be requested via the Accept-Encoding header and used to
+// decode compressed response bodies. GzipCodec and DeflateCodec are used
+// if no codecs are specified. The header is only set for requests that do
+// not already specify one. Note that net/http transparently requests and
+// decodes gzip responses by default but does not report the size of the
+// compressed body; when compression is enabled via this option the number
+// of bytes received on the wire is reported in Response.CompressedSize.
+func WithCompression(codecs ...Codec) Option {
+	return func(o *options) {
+		if len(codecs) == 0 {
+			codecs = []Codec{GzipCodec, DeflateCodec}
+		}
+		o.codecs = map[string]Codec{}
+		names := make([]string, 0, len(codecs))
+		for i, c := range codecs {
+			name := strings.ToLower(c.Name())
+			o.codecs[name] = c
+			if i > 0 {
+				name = fmt.Sprintf("%s;q=%.1f", name, max(0.1, 1-float64(i)/10))
+			}
+			names = append(names, name)
+		}
+		o.acceptEncoding = strings.Join(names, ", ")
+	}
+}
+
+// decodedBody is the body of a response that is decoded as it is read.
+// It records the number of bytes read from the original body.
+type decodedBody struct {
+	body       io.ReadCloser
+	raw        *countingReader
+	encodings  []string
+	codecs     map[string]Codec
+	rd         io.Reader
+	decoders   []io.Closer
+	err        error
+	compressed string
+}
+
+// countingReader counts the number of bytes read from an io.Reader.
+type countingReader struct {
+	rd io.Reader
+	n  int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.rd.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// Read implements io.Reader. The decoders are created on the first call
+// to Read since some, eg. gzip, read from the body when created.
+func (db *decodedBody) Read(p []byte) (int, error) {
+	if db.err != nil {
+		return 0, db.err
+	}
+	if db.rd == nil {
+		if db.err = db.init(); db.err != nil {
+			return 0, db.err
+		}
+	}
+	return db.rd.Read(p)
+}
+
+func (db *decodedBody) init() error {
+	var rd io.Reader = db.raw
+	// Content codings are listed in the order in which they were applied.
+	for i := len(db.encodings) - 1; i >= 0; i-- {
+		dec, err := db.codecs[db.encodings[i]].NewReader(rd)
+		if err != nil {
+			return fmt.Errorf("%w: failed to create %v decoder: %w", ErrDecode, db.encodings[i], err)
+		}
+		db.decoders = append(db.decoders, dec)
+		rd = dec
+	}
+	db.rd = rd
+	return nil
+}
+
+// Close implements io.Closer.
+func (db *decodedBody) Close() error {
+	for _, dec := range db.decoders {
+		dec.Close()
+	}
+	return db.body.Close()
+}
+
+// decodeResponse replaces the body of resp with one that decodes its
+// content codings, if any, using the configured codecs. As for net/http's
+// transparent gzip decoding, the Content-Encoding and Content-Length
+// headers are removed and resp.Uncompressed is set. An error is returned
+// if the response uses a content coding for which there is no codec.
+func (ep *Endpoint[T]) decodeResponse(resp *http.Response) error {
+	if len(ep.codecs) == 0 || resp.Body == nil || resp.Body == http.NoBody || resp.ContentLength == 0 {
+		return nil
+	}
+	var encodings []string
+	for _, v := range resp.Header.Values("Content-Encoding") {
+		for enc := range strings.SplitSeq(v, ",") {
+			enc = strings.ToLower(strings.TrimSpace(enc))
+			if len(enc) == 0 || enc == "identity" {
+				continue
+			}
+			if _, ok := ep.codecs[enc]; !ok {
+				return fmt.Errorf("%w: unsupported content encoding %q", ErrDecode, enc)
+			}
+			encodings = append(encodings, enc)
+		}
+	}
+	if len(encodings) == 0 {
+		return nil
+	}
+	resp.Body = &decodedBody{
+		body:       resp.Body,
+		raw:        &countingReader{rd: resp.Body},
+		encodings:  encodings,
+		codecs:     ep.codecs,
+		compressed: strings.Join(encodings, ", "),
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// compressedSize returns the content codings used for, and the number of
+// bytes read from, the original body of a response decoded by
+// decodeResponse.
+func compressedSize(resp *http.Response) (string, int64) {
+	if resp == nil {
+		return "", 0
+	}
+	db, ok := resp.Body.(*decodedBody)
+	if !ok {
+		return "", 0
+	}
+	return db.compressed, db.raw.n
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+// reverseCodec is a trivial content coding used to test user supplied
+// codecs.
+var reverseCodec = operations.NewCodec("x-reverse", func(rd io.Reader) (io.ReadCloser, error) {
+	buf, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(reverse(buf))), nil
+})
+
+func reverse(buf []byte) []byte {
+	r := make([]byte, len(buf))
+	for i, b := range buf {
+		r[len(buf)-1-i] = b
+	}
+	return r
+}
+
+func compress(t *testing.T, enc string, data []byte) []byte {
+	var out bytes.Buffer
+	var wr io.WriteCloser
+	switch enc {
+	case "gzip":
+		wr = gzip.NewWriter(&out)
+	case "deflate":
+		wr = zlib.NewWriter(&out)
+	case "raw-deflate":
+		wr, _ = flate.NewWriter(&out, flate.DefaultCompression)
+	case "x-reverse":
+		return reverse(data)
+	default:
+		return data
+	}
+	if _, err := wr.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := wr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestCompression(t *testing.T) {
+	ctx := context.Background()
+	data := []byte(`{"Name":"` + strings.Repeat("foo", 1000) + `","Value":42}`)
+	var accepted string
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted = r.Header.Get("Accept-Encoding")
+		body := data
+		encs := r.URL.Query()["enc"]
+		for _, enc := range encs {
+			body = compress(t, enc, body)
+		}
+		if len(encs) > 0 {
+			w.Header().Set("Content-Encoding", strings.ReplaceAll(strings.Join(encs, ", "), "raw-", ""))
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	ep := operations.NewEndpoint[example](operations.WithCompression(reverseCodec, operations.GzipCodec, operations.DeflateCodec))
+	for _, tc := range []struct {
+		query string
+		enc   string
+	}{
+		{"enc=gzip", "gzip"},
+		{"enc=deflate", "deflate"},
+		{"enc=raw-deflate", "deflate"},
+		{"enc=x-reverse", "x-reverse"},
+		{"enc=gzip&enc=x-reverse", "gzip, x-reverse"},
+		{"", ""},
+	} {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?"+tc.query, nil)
+		v, resp, err := ep.Do(ctx, req)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.query, err)
+		}
+		if got, want := v.Value, 42; got != want {
+			t.Errorf("%v: got %v, want %v", tc.query, got, want)
+		}
+		if got, want := resp.Bytes, data; !bytes.Equal(got, want) {
+			t.Errorf("%v: got %s, want %s", tc.query, got, want)
+		}
+		if got, want := resp.ContentEncoding, tc.enc; got != want {
+			t.Errorf("%v: got %v, want %v", tc.query, got, want)
+		}
+		if len(tc.enc) == 0 {
+			if got, want := resp.CompressedSize, int64(0); got != want {
+				t.Errorf("%v: got %v, want %v", tc.query, got, want)
+			}
+			continue
+		}
+		if got, limit := resp.CompressedSize, int64(len(data)); got <= 0 || (tc.enc != "x-reverse" && got >= limit) {
+			t.Errorf("%v: compressed size %v, original size %v", tc.query, got, limit)
+		}
+	}
+	if got, want := accepted, "x-reverse, gzip;q=0.9, deflate;q=0.8"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	ep = operations.NewEndpoint[example](operations.WithCompression())
+	_, _, _, err := ep.Get(ctx, srv.URL+"?enc=x-reverse")
+	if !errors.Is(err, operations.ErrDecode) {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+	if got, want := accepted, "gzip, deflate;q=0.9"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?enc=gzip", nil)
+	stream, err := ep.Stream(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	buf, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf, data; !bytes.Equal(got, want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if enc, size := stream.CompressedSize(); enc != "gzip" || size == 0 || size >= int64(len(data)) {
+		t.Errorf("unexpected encoding or size: %v, %v", enc, size)
+	}
+}
@@ -7,8 +7,10 @@ package operations
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
+	"cloudeng.io/file/checkpoint"
 	"cloudeng.io/file/content"
 	"cloudeng.io/logging/ctxlog"
 )
@@ -31,6 +33,27 @@ type Response struct {
 	ProtoMajor, ProtoMinir int
 	TransferEncoding       []string
 
+	// ContentEncoding is the content coding, eg. gzip, that was used
+	// for the response body as received and CompressedSize is the number
+	// of bytes of the body that were received, ie. prior to it being
+	// decoded. They are only set when the response was decoded using a
+	// Codec specified via WithCompression.
+	ContentEncoding string
+	CompressedSize  int64
+
+	// Empty is true if the response has no body, eg. a 204 No Content
+	// response or a response to a HEAD request.
+	Empty bool
+
+	// CacheHit is true if the response was obtained from a ResponseCache
+	// following a 304 Not Modified response from the server.
+	CacheHit bool
+
+	// Hedged is true if a hedged request was sent, see WithHedging, and
+	// HedgeWon is true if the response is that received for the hedged
+	// request rather than for the original request.
+	Hedged, HedgeWon bool
+
 	// Any error encountered during the operation.
 	Error error
 
@@ -69,26 +92,57 @@ type Fetcher[ScanT, EndpointT any] interface {
 type Crawler[ScanT any, EndpointT any] struct {
 	scanner *Scanner[ScanT]
 	fetcher Fetcher[ScanT, EndpointT]
+	opts    crawlOptions
+}
+
+// CrawlOption represents an option for configuring a Crawler.
+type CrawlOption func(o *crawlOptions)
+
+type crawlOptions struct {
+	fetchConcurrency int
+	checkpoint       checkpoint.Operation
+	metrics          Metrics
+	metricLabels     MetricLabels
+}
+
+// WithFetchConcurrency specifies the number of scanned pages for which
+// the Fetcher may be called concurrently. The objects sent by the Fetcher
+// for each page are delivered in the order in which the pages were scanned
+// and all of the objects for a given page are delivered before any for
+// the next page. Consequently any checkpoint included with the last object
+// for a page is only seen once all preceding pages have been delivered.
+// The default is 1, ie. the Fetcher is called for one page at a time.
+func WithFetchConcurrency(n int) CrawlOption {
+	return func(o *crawlOptions) {
+		o.fetchConcurrency = n
+	}
 }
 
 // NewCrawler creates a new crawler that scans the API using
 // the provided Scanner with the result of each scan being passed
 // to the Fetcher to download each item returned by the scan.
-func NewCrawler[ScanT, EndpointT any](scanner *Scanner[ScanT], fetcher Fetcher[ScanT, EndpointT]) *Crawler[ScanT, EndpointT] {
-	return &Crawler[ScanT, EndpointT]{
+func NewCrawler[ScanT, EndpointT any](scanner *Scanner[ScanT], fetcher Fetcher[ScanT, EndpointT], opts ...CrawlOption) *Crawler[ScanT, EndpointT] {
+	c := &Crawler[ScanT, EndpointT]{
 		scanner: scanner,
 		fetcher: fetcher,
 	}
+	for _, fn := range opts {
+		fn(&c.opts)
+	}
+	return c
 }
 
 // Run runs the crawler. It consists of a scan loop that calls
 // a Fetcher for each scan response.
 func (c *Crawler[ScanT, EndpointT]) Run(ctx context.Context, ch chan<- []content.Object[EndpointT, Response]) error {
 	defer close(ch)
+	if c.opts.fetchConcurrency > 1 {
+		return c.runConcurrently(ctx, ch)
+	}
 	i := 0
 	for c.scanner.Scan(ctx) {
 		resp := c.scanner.Response()
-		if err := c.fetcher.Fetch(ctx, resp, ch); err != nil {
+		if err := c.fetch(ctx, i, resp, ch); err != nil {
 			return err
 		}
 		i++
@@ -96,11 +150,104 @@ func (c *Crawler[ScanT, EndpointT]) Run(ctx context.Context, ch chan<- []content
 	return c.scanner.Err()
 }
 
+// runConcurrently calls the Fetcher for up to fetchConcurrency pages at a
+// time. Each call is given its own channel and these channels are forwarded,
+// in scan order, to ch. Once any Fetch fails no further objects are
+// forwarded so that a checkpoint for a later page is never delivered
+// when an earlier page was not completely fetched.
+func (c *Crawler[ScanT, EndpointT]) runConcurrently(ctx context.Context, ch chan<- []content.Object[EndpointT, Response]) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	n := c.opts.fetchConcurrency
+	pending := make(chan chan []content.Object[EndpointT, Response], n)
+	sem := make(chan struct{}, n)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		for pageCh := range pending {
+			for objs := range pageCh {
+				if ctx.Err() != nil {
+					continue // drain, but do not forward.
+				}
+				select {
+				case ch <- objs:
+				case <-ctx.Done():
+				}
+			}
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		cancel()
+	}
+
+	i := 0
+scan:
+	for c.scanner.Scan(ctx) {
+		page := c.scanner.Response()
+		pageCh := make(chan []content.Object[EndpointT, Response], 1)
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break scan
+		}
+		select {
+		case pending <- pageCh:
+		case <-ctx.Done():
+			<-sem
+			break scan
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer close(pageCh)
+			if err := c.fetch(ctx, i, page, pageCh); err != nil {
+				setErr(err)
+			}
+		}(i)
+		i++
+	}
+	wg.Wait()
+	close(pending)
+	<-forwarded
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := c.scanner.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (c *Crawler[ScanT, EndpointT]) fetch(ctx context.Context, page int, resp ScanT, ch chan<- []content.Object[EndpointT, Response]) error {
+	ctx, span := StartSpan(ctx, SpanFetchPage, Attr("webapi.page.index", page))
+	err := c.fetcher.Fetch(ctx, resp, ch)
+	endSpan(span, err)
+	return err
+}
+
 type CrawlHandler[EndpointT any] func(context.Context, []content.Object[EndpointT, Response]) error
 
 // RunCrawl is a convenience function that runs a crawler and calls the supplied
-// handler for each Object crawled.
-func RunCrawl[ScanT, EndpointT any](ctx context.Context, crawler *Crawler[ScanT, EndpointT], handler CrawlHandler[EndpointT]) error {
+// handler for each Object crawled. Any options supplied are applied to the
+// crawler before it is run, see WithCheckpoint for automatically saving
+// the progress of the crawl.
+func RunCrawl[ScanT, EndpointT any](ctx context.Context, crawler *Crawler[ScanT, EndpointT], handler CrawlHandler[EndpointT], opts ...CrawlOption) error {
+	for _, fn := range opts {
+		fn(&crawler.opts)
+	}
 
 	errCh := make(chan error)
 	ch := make(chan []content.Object[EndpointT, Response])
@@ -123,6 +270,12 @@ func RunCrawl[ScanT, EndpointT any](ctx context.Context, crawler *Crawler[ScanT,
 			if err := handler(ctx, objs); err != nil {
 				return err
 			}
+			if err := saveLatestCheckpoint(ctx, crawler.opts.checkpoint, objs); err != nil {
+				return err
+			}
+			if m := crawler.opts.metrics; m != nil {
+				m.Objects(crawler.opts.metricLabels, len(objs))
+			}
 		}
 	}
 }
@@ -9,7 +9,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -102,3 +104,226 @@ func TestCrawler(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+type slowFetcher struct {
+	fetcher
+	mu          sync.Mutex
+	inflight    int
+	maxInflight int
+	failAt      int
+}
+
+func (f *slowFetcher) Fetch(ctx context.Context, page webapitestutil.Paginated, ch chan<- []content.Object[Object, operations.Response]) error {
+	f.mu.Lock()
+	f.inflight++
+	f.maxInflight = max(f.maxInflight, f.inflight)
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inflight--
+		f.mu.Unlock()
+	}()
+	if f.failAt != 0 && page.Current == f.failAt {
+		return fmt.Errorf("failed at %v", page.Current)
+	}
+	// Make earlier pages slower to ensure that fetches complete out of order.
+	time.Sleep(time.Duration(10-page.Current) * time.Millisecond)
+	return f.fetcher.Fetch(ctx, page, ch)
+}
+
+func TestCrawlerConcurrency(t *testing.T) {
+	ctx := context.Background()
+	for _, failAt := range []int{0, 5} {
+		mux := http.NewServeMux()
+		mux.Handle("/list", &webapitestutil.PaginatedHandler{
+			Last: 10,
+		})
+		mux.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(Object{ID: r.URL.Query().Get("id")})
+		})
+		srv := webapitestutil.NewServer(mux)
+		defer srv.Close()
+
+		paginator := &paginator{url: srv.URL + "/list"}
+		scanner := operations.NewScanner[webapitestutil.Paginated](paginator)
+		fetcher := &slowFetcher{fetcher: fetcher{url: srv.URL, ep: operations.NewEndpoint[Object]()}, failAt: failAt}
+		cr := operations.NewCrawler[webapitestutil.Paginated, Object](scanner, fetcher)
+
+		var ids []string
+		err := operations.RunCrawl(ctx, cr, func(_ context.Context, objs []content.Object[Object, operations.Response]) error {
+			for _, obj := range objs {
+				ids = append(ids, obj.Value.ID)
+			}
+			return nil
+		}, operations.WithFetchConcurrency(3))
+
+		if failAt == 0 {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(ids), 11; got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		} else if err == nil || err.Error() != "failed at 5" {
+			t.Errorf("missing or unexpected error: %v", err)
+		}
+		// Pages must always be delivered in order and without gaps.
+		for i, id := range ids {
+			if got, want := id, fmt.Sprintf("%v", i+1); got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+		if failAt != 0 && len(ids) > failAt {
+			t.Errorf("pages after the failed page were delivered: %v", ids)
+		}
+		if fetcher.maxInflight < 2 || fetcher.maxInflight > 3 {
+			t.Errorf("unexpected concurrency: %v", fetcher.maxInflight)
+		}
+	}
+}
+
+func TestFetchItems(t *testing.T) {
+	ctx := context.Background()
+	items := []int{5, 4, 3, 2, 1, 0}
+	var inflight, maxInflight atomic.Int64
+	double := func(_ context.Context, i int) (int, error) {
+		n := inflight.Add(1)
+		for {
+			m := maxInflight.Load()
+			if n <= m || maxInflight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(i) * time.Millisecond)
+		inflight.Add(-1)
+		return i * 2, nil
+	}
+	for _, concurrency := range []int{1, 3} {
+		maxInflight.Store(0)
+		results, err := operations.FetchItems(ctx, concurrency, items, double)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := results, []int{10, 8, 6, 4, 2, 0}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if got, want := maxInflight.Load(), int64(concurrency); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	_, err := operations.FetchItems(ctx, 2, items, func(_ context.Context, i int) (int, error) {
+		if i == 3 {
+			return 0, fmt.Errorf("oops")
+		}
+		return i, nil
+	})
+	if err == nil || err.Error() != "oops" {
+		t.Errorf("missing or unexpected error: %v", err)
+	}
+}
+
+type memCheckpoint struct {
+	mu     sync.Mutex
+	states [][]byte
+}
+
+func (m *memCheckpoint) Init(context.Context, string) error { return nil }
+func (m *memCheckpoint) Checkpoint(_ context.Context, _ string, data []byte) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states = append(m.states, data)
+	return fmt.Sprintf("%08d", len(m.states)), nil
+}
+func (m *memCheckpoint) Latest(context.Context) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.states) == 0 {
+		return nil, nil
+	}
+	return m.states[len(m.states)-1], nil
+}
+func (m *memCheckpoint) Complete(context.Context) error        { return nil }
+func (m *memCheckpoint) Clear(context.Context) error           { m.states = nil; return nil }
+func (m *memCheckpoint) Compact(context.Context, string) error { return nil }
+func (m *memCheckpoint) Load(context.Context, string) ([]byte, error) {
+	return nil, nil
+}
+
+type pageState struct {
+	Page int `json:"page"`
+}
+
+type checkpointingFetcher struct {
+	fetcher
+}
+
+func (f *checkpointingFetcher) Fetch(ctx context.Context, page webapitestutil.Paginated, ch chan<- []content.Object[Object, operations.Response]) error {
+	objs := []content.Object[Object, operations.Response]{
+		{Value: Object{ID: fmt.Sprintf("%v-a", page.Current)}},
+		{Value: Object{ID: fmt.Sprintf("%v-b", page.Current)}},
+	}
+	// Only odd pages carry a checkpoint, and then only on their first object.
+	if page.Current%2 == 1 {
+		buf, _ := json.Marshal(pageState{Page: page.Current})
+		objs[0].Response.Checkpoint = buf
+	}
+	ch <- objs
+	return nil
+}
+
+func TestCrawlerCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	mux.Handle("/list", &webapitestutil.PaginatedHandler{
+		Last: 10,
+	})
+	srv := webapitestutil.NewServer(mux)
+	defer srv.Close()
+
+	op := &memCheckpoint{}
+	var state pageState
+	ok, err := operations.LoadCheckpoint(ctx, op, &state)
+	if err != nil || ok {
+		t.Fatalf("unexpected checkpoint: %v, %v", ok, err)
+	}
+
+	paginator := &paginator{url: srv.URL + "/list"}
+	scanner := operations.NewScanner[webapitestutil.Paginated](paginator)
+	cr := operations.NewCrawler[webapitestutil.Paginated, Object](scanner, &checkpointingFetcher{})
+
+	handled := 0
+	err = operations.RunCrawl(ctx, cr, func(_ context.Context, objs []content.Object[Object, operations.Response]) error {
+		if objs[0].Value.ID == "6-a" {
+			return fmt.Errorf("handler failed")
+		}
+		handled++
+		return nil
+	}, operations.WithCheckpoint(op))
+	if err == nil || err.Error() != "handler failed" {
+		t.Fatalf("missing or unexpected error: %v", err)
+	}
+	if got, want := handled, 6; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// Pages 1, 3 and 5 were checkpointed, page 6 failed.
+	if got, want := len(op.states), 3; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	ok, err = operations.LoadCheckpoint(ctx, op, &state)
+	if err != nil || !ok {
+		t.Fatalf("missing checkpoint: %v, %v", ok, err)
+	}
+	if got, want := state.Page, 5; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := operations.SaveCheckpoint(ctx, op, pageState{Page: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := operations.LoadCheckpoint(ctx, op, &state); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := state.Page, 7; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
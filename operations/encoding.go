@@ -0,0 +1,183 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Encoding represents the encoding scheme used for the response body.
+// Encodings are recorded in operations.Response and hence their values
+// must not change.
+type Encoding int
+
+const (
+	JSONEncoding     Encoding = iota
+	XMLEncoding               // XML as decoded by encoding/xml.
+	NDJSONEncoding            // Newline delimited JSON, aka JSON lines.
+	CSVEncoding               // Comma separated values, see Encoding.Unmarshal.
+	GzipJSONEncoding          // gzip compressed JSON.
+)
+
+// String implements fmt.Stringer.
+func (e Encoding) String() string {
+	switch e {
+	case JSONEncoding:
+		return "json"
+	case XMLEncoding:
+		return "xml"
+	case NDJSONEncoding:
+		return "ndjson"
+	case CSVEncoding:
+		return "csv"
+	case GzipJSONEncoding:
+		return "gzip+json"
+	}
+	return fmt.Sprintf("unknown encoding: %d", int(e))
+}
+
+// ContentType returns the canonical MIME type for the encoding.
+func (e Encoding) ContentType() string {
+	switch e {
+	case XMLEncoding:
+		return "application/xml"
+	case NDJSONEncoding:
+		return "application/x-ndjson"
+	case CSVEncoding:
+		return "text/csv"
+	case GzipJSONEncoding:
+		return "application/gzip"
+	}
+	return "application/json"
+}
+
+// Unmarshal decodes data, which is assumed to be encoded using e, into v.
+// It may be used to decode the Bytes stored in an operations.Response.
+// For NDJSONEncoding v must be a pointer to a slice to which each
+// decoded line is appended. For CSVEncoding v must be either a pointer
+// to a [][]string, in which case all records are appended, or a pointer to
+// a []map[string]string, in which case the first record is treated as
+// a header and each subsequent record is appended as map keyed by
+// the header's column names.
+func (e Encoding) Unmarshal(data []byte, v any) error {
+	switch e {
+	case JSONEncoding:
+		return json.Unmarshal(data, v)
+	case XMLEncoding:
+		return xml.Unmarshal(data, v)
+	case NDJSONEncoding:
+		return unmarshalNDJSON(data, v)
+	case CSVEncoding:
+		return unmarshalCSV(data, v)
+	case GzipJSONEncoding:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return json.NewDecoder(gz).Decode(v)
+	}
+	return fmt.Errorf("unsupported encoding: %v", e)
+}
+
+// EncodingForContentType returns the Encoding appropriate for the
+// specified content type (ie. the value of a Content-Type header) and
+// false if the content type is not recognised.
+func EncodingForContentType(contentType string) (Encoding, bool) {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return JSONEncoding, false
+	}
+	switch mt {
+	case "application/json", "text/json":
+		return JSONEncoding, true
+	case "application/xml", "text/xml":
+		return XMLEncoding, true
+	case "application/x-ndjson", "application/ndjson", "application/jsonl",
+		"application/x-jsonlines", "application/jsonlines", "application/json-lines":
+		return NDJSONEncoding, true
+	case "text/csv", "application/csv":
+		return CSVEncoding, true
+	case "application/gzip", "application/x-gzip":
+		return GzipJSONEncoding, true
+	}
+	switch {
+	case strings.HasSuffix(mt, "+json"):
+		return JSONEncoding, true
+	case strings.HasSuffix(mt, "+xml"):
+		return XMLEncoding, true
+	}
+	return JSONEncoding, false
+}
+
+// EncodingForResponse returns the Encoding to use for the body of the
+// supplied response based on its Content-Type header, defaulting to
+// JSONEncoding. JSON bodies with a gzip Content-Encoding that was not
+// transparently removed by net/http are treated as GzipJSONEncoding.
+func EncodingForResponse(resp *http.Response) Encoding {
+	enc, _ := EncodingForContentType(resp.Header.Get("Content-Type"))
+	if enc == JSONEncoding && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return GzipJSONEncoding
+	}
+	return enc
+}
+
+func unmarshalNDJSON(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("ndjson: %T is not a pointer to a slice", v)
+	}
+	slice := rv.Elem()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		item := reflect.New(slice.Type().Elem())
+		if err := dec.Decode(item.Interface()); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		slice.Set(reflect.Append(slice, item.Elem()))
+	}
+}
+
+func unmarshalCSV(data []byte, v any) error {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	switch out := v.(type) {
+	case *[][]string:
+		*out = append(*out, records...)
+		return nil
+	case *[]map[string]string:
+		if len(records) == 0 {
+			return nil
+		}
+		header := records[0]
+		for _, rec := range records[1:] {
+			row := make(map[string]string, len(header))
+			for i, col := range header {
+				if i < len(rec) {
+					row[col] = rec[i]
+				}
+			}
+			*out = append(*out, row)
+		}
+		return nil
+	}
+	return fmt.Errorf("csv: %T is neither a *[][]string nor a *[]map[string]string", v)
+}
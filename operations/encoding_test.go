@@ -0,0 +1,132 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func TestEncodingForContentType(t *testing.T) {
+	for _, tc := range []struct {
+		ct    string
+		enc   operations.Encoding
+		known bool
+	}{
+		{"application/json; charset=utf-8", operations.JSONEncoding, true},
+		{"application/geo+json", operations.JSONEncoding, true},
+		{"text/xml", operations.XMLEncoding, true},
+		{"application/atom+xml", operations.XMLEncoding, true},
+		{"application/x-ndjson", operations.NDJSONEncoding, true},
+		{"text/csv; header=present", operations.CSVEncoding, true},
+		{"application/gzip", operations.GzipJSONEncoding, true},
+		{"text/plain", operations.JSONEncoding, false},
+		{"", operations.JSONEncoding, false},
+	} {
+		enc, known := operations.EncodingForContentType(tc.ct)
+		if got, want := enc, tc.enc; got != want {
+			t.Errorf("%q: got %v, want %v", tc.ct, got, want)
+		}
+		if got, want := known, tc.known; got != want {
+			t.Errorf("%q: got %v, want %v", tc.ct, got, want)
+		}
+	}
+}
+
+func TestEncodingNegotiation(t *testing.T) {
+	ctx := context.Background()
+	var gz bytes.Buffer
+	gzw := gzip.NewWriter(&gz)
+	_, _ = gzw.Write([]byte(`[{"Name":"gz","Value":4}]`))
+	gzw.Close()
+
+	bodies := map[string][]byte{
+		"application/json":     []byte(`[{"Name":"json","Value":0}]`),
+		"application/xml":      []byte(`<example><Name>xml</Name><Value>1</Value></example>`),
+		"application/x-ndjson": []byte("{\"Name\":\"nd\",\"Value\":2}\n{\"Name\":\"nd\",\"Value\":3}\n"),
+		"application/gzip":     gz.Bytes(),
+	}
+	var accept string
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		ct := r.URL.Query().Get("ct")
+		w.Header().Set("Content-Type", ct)
+		_, _ = w.Write(bodies[ct])
+	}))
+	defer srv.Close()
+
+	client := operations.NewEndpoint[[]example](
+		operations.WithAccept(operations.JSONEncoding, operations.NDJSONEncoding))
+	for _, tc := range []struct {
+		ct  string
+		enc operations.Encoding
+		val []example
+	}{
+		{"application/json", operations.JSONEncoding, []example{{"json", 0}}},
+		{"application/x-ndjson", operations.NDJSONEncoding, []example{{"nd", 2}, {"nd", 3}}},
+		{"application/gzip", operations.GzipJSONEncoding, []example{{"gz", 4}}},
+	} {
+		val, body, enc, err := client.Get(ctx, srv.URL+"?ct="+tc.ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := enc, tc.enc; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if got, want := val, tc.val; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		var stored []example
+		if err := enc.Unmarshal(body, &stored); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := stored, tc.val; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	if got, want := accept, "application/json, application/x-ndjson;q=0.9"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	xmlClient := operations.NewEndpoint[example]()
+	val, _, enc, err := xmlClient.Get(ctx, srv.URL+"?ct=application/xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := enc, operations.XMLEncoding; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := val, (example{"xml", 1}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCSVEncoding(t *testing.T) {
+	data := []byte("name,value\nfoo,1\nbar,2\n")
+	var records [][]string
+	if err := operations.CSVEncoding.Unmarshal(data, &records); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(records), 3; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	var rows []map[string]string
+	if err := operations.CSVEncoding.Unmarshal(data, &rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rows, []map[string]string{{"name": "foo", "value": "1"}, {"name": "bar", "value": "2"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if err := operations.CSVEncoding.Unmarshal(data, &example{}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -5,15 +5,53 @@
 package operations
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 )
 
+// Sentinel errors that can be used with errors.Is to classify the errors
+// returned by an Endpoint. ErrRateLimited, ErrUnauthorized, ErrForbidden,
+// ErrNotFound and ErrServerError are determined by the status code of
+// the final response received.
+var (
+	ErrRateLimited  = errors.New("rate limited")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrServerError  = errors.New("server error")
+	ErrDecode       = errors.New("failed to decode response")
+	ErrNetwork      = errors.New("network error")
+)
+
+// MaxErrorBodySize is the maximum number of bytes of the body of an
+// error response that are retained in Error.Body.
+const MaxErrorBodySize = 4096
+
+// Attempt describes a single attempt at issuing a request.
+type Attempt struct {
+	When       time.Time
+	Duration   time.Duration
+	StatusCode int   // zero if no response was received.
+	Err        error // any error returned by the http.Client.
+}
+
 type Error struct {
 	Err        error
 	Status     string
 	StatusCode int
 	Attempts   int
+
+	// The method and URL of the failed request.
+	Method, URL string
+	// Body contains, at most, the first MaxErrorBodySize bytes of the
+	// body of the final response received, if any.
+	Body []byte
+	// History records every attempt made to issue the request.
+	History []Attempt
 }
 
 func (err *Error) Error() string {
@@ -23,8 +61,31 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("%v: %v", err.Status, err.Err)
 }
 
+// Unwrap returns the underlying error, if any.
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Is implements errors.Is for the sentinel errors that are determined by
+// the status code of the final response received.
+func (err *Error) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return err.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return err.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return err.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return err.StatusCode == http.StatusNotFound || err.StatusCode == http.StatusGone
+	case ErrServerError:
+		return err.StatusCode >= 500 && err.StatusCode < 600
+	}
+	return false
+}
+
 func handleError(err error, status string, statusCode int, attempts int) error {
-	if err == nil && statusCode == http.StatusOK {
+	if err == nil && statusCode >= 200 && statusCode < 300 {
 		return nil
 	}
 	return &Error{
@@ -34,3 +95,93 @@ func handleError(err error, status string, statusCode int, attempts int) error {
 		Attempts:   attempts,
 	}
 }
+
+// networkError wraps errors returned by an http.Client with ErrNetwork,
+// unless the request was canceled.
+func networkError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrNetwork, err)
+}
+
+// readErrorBody reads, and closes, the body of an error response
+// retaining at most MaxErrorBodySize bytes.
+func readErrorBody(body io.ReadCloser) []byte {
+	defer body.Close()
+	buf, _ := io.ReadAll(io.LimitReader(body, MaxErrorBodySize))
+	// Drain, up to a limit, any remaining data so that the connection
+	// can be reused.
+	_, _ = io.CopyN(io.Discard, body, 64*1024)
+	return buf
+}
+
+// annotateError adds the request method, redacted URL and attempt
+// history to an *Error.
+func (ep *Endpoint[T]) annotateError(req *http.Request, history []Attempt, err error) {
+	operr, ok := err.(*Error)
+	if !ok {
+		return
+	}
+	operr.Method = req.Method
+	if len(operr.Method) == 0 {
+		operr.Method = http.MethodGet
+	}
+	operr.URL = ep.redactedURL(req.URL)
+	operr.History = history
+}
+
+// ErrorAction represents the action that a crawl may take following
+// an error.
+type ErrorAction int
+
+const (
+	// Abort indicates that the crawl should be aborted.
+	Abort ErrorAction = iota
+	// Skip indicates that the failed item should be skipped and the
+	// crawl continued.
+	Skip
+	// RetryLater indicates that the failure is likely to be transient
+	// and that the crawl should be stopped and resumed later.
+	RetryLater
+)
+
+func (a ErrorAction) String() string {
+	switch a {
+	case Skip:
+		return "skip"
+	case RetryLater:
+		return "retry-later"
+	}
+	return "abort"
+}
+
+// ActionForError suggests the action to take following err:
+//   - RetryLater for ErrRateLimited, ErrServerError, ErrNetwork,
+//     ErrCircuitOpen and http.StatusRequestTimeout,
+//   - Abort for ErrUnauthorized,
+//   - Skip for ErrDecode and all other 4xx status codes, including
+//     ErrNotFound and ErrForbidden, since these are specific to the
+//     failed request,
+//   - Abort for all other errors.
+func ActionForError(err error) ErrorAction {
+	switch {
+	case errors.Is(err, ErrRateLimited), errors.Is(err, ErrServerError),
+		errors.Is(err, ErrNetwork), errors.Is(err, ErrCircuitOpen):
+		return RetryLater
+	case errors.Is(err, ErrUnauthorized):
+		return Abort
+	case errors.Is(err, ErrDecode):
+		return Skip
+	}
+	var operr *Error
+	if errors.As(err, &operr) {
+		switch {
+		case operr.StatusCode == http.StatusRequestTimeout:
+			return RetryLater
+		case operr.StatusCode >= 400 && operr.StatusCode < 500:
+			return Skip
+		}
+	}
+	return Abort
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"sync"
+)
+
+// FetchItems is a helper for Fetcher implementations that calls fetch for
+// each of the supplied items, using at most concurrency goroutines, and
+// returns the results in the same order as the items. It returns the first
+// error encountered, in which case the context passed to any outstanding
+// calls to fetch is canceled. A concurrency of less than 1 is treated as 1.
+func FetchItems[ItemT, ResultT any](ctx context.Context, concurrency int, items []ItemT, fetch func(context.Context, ItemT) (ResultT, error)) ([]ResultT, error) {
+	results := make([]ResultT, len(items))
+	if concurrency <= 1 {
+		for i, item := range items {
+			r, err := fetchItem(ctx, i, item, fetch)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = r
+		}
+		return results, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, item ItemT) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r, err := fetchItem(ctx, i, item, fetch)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			results[i] = r
+		}(i, item)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func fetchItem[ItemT, ResultT any](ctx context.Context, index int, item ItemT, fetch func(context.Context, ItemT) (ResultT, error)) (ResultT, error) {
+	ctx, span := StartSpan(ctx, SpanFetchItem, Attr("webapi.item.index", index))
+	r, err := fetch(ctx, item)
+	endSpan(span, err)
+	return r, err
+}
@@ -7,13 +7,16 @@
 package operations
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -21,13 +24,6 @@ import (
 	"cloudeng.io/net/ratecontrol"
 )
 
-// Encoding represents the encoding scheme used for the response body.
-type Encoding int
-
-const (
-	JSONEncoding Encoding = iota
-)
-
 // Endpoint represents an API endpoint that whose response body is unmarshaled,
 // by default using json.Unmarshal, into the specified type.
 type Endpoint[T any] struct {
@@ -40,12 +36,26 @@ func NewEndpoint[T any](opts ...Option) *Endpoint[T] {
 	for _, fn := range opts {
 		fn(&ep.options)
 	}
-	if ep.rateController == nil {
+	if ep.rateController == nil && ep.sharedRateControl == nil {
 		ep.rateController = ratecontrol.New()
 	}
 	if ep.unmarshal == nil {
 		ep.unmarshal = json.Unmarshal
-		ep.encoding = JSONEncoding
+		ep.autoEncoding = ep.streamUnmarshal == nil
+		if ep.autoEncoding {
+			ep.encoding = JSONEncoding
+		}
+	}
+	if ep.client == nil {
+		ep.client = http.DefaultClient
+	}
+	if ep.rateLimitHeaders == nil {
+		h := DefaultRateLimitHeaders()
+		ep.rateLimitHeaders = &h
+	}
+	if ep.marshal == nil {
+		ep.marshal = json.Marshal
+		ep.contentType = "application/json"
 	}
 	return ep
 }
@@ -64,13 +74,74 @@ func (ep *Endpoint[T]) Get(ctx context.Context, url string) (T, []byte, Encoding
 // supplied http.Request. The Body in the http.Response has already been
 // read and its contents returned as the second return value.
 func (ep *Endpoint[T]) IssueRequest(ctx context.Context, req *http.Request) (T, []byte, Encoding, *http.Response, error) {
-	t, r, b, err := ep.getWithResp(ctx, req)
-	return t, b, ep.encoding, r, err
+	r, err := ep.getWithResp(ctx, req)
+	return r.value, r.body, r.encoding, r.resp, err
 }
 
 func (ep *Endpoint[T]) get(ctx context.Context, req *http.Request) (T, []byte, Encoding, error) {
-	t, _, b, err := ep.getWithResp(ctx, req)
-	return t, b, ep.encoding, err
+	r, err := ep.getWithResp(ctx, req)
+	return r.value, r.body, r.encoding, err
+}
+
+// result represents the outcome of a single request.
+type result[T any] struct {
+	value    T
+	resp     *http.Response
+	body     []byte
+	encoding Encoding
+	empty    bool
+	cacheHit bool
+	hedged   bool
+	hedgeWon bool
+	streamed bool  // set for responses returned as a Stream.
+	received int64 // bytes read from a Stream.
+}
+
+// Do invokes an arbitrary request on this endpoint using the supplied
+// http.Request and returns the decoded response body along with
+// a Response that describes the response received, including its
+// status code, and whether it has an empty body (eg. a 204 No Content
+// response or a response to a HEAD request). Note that the Response is
+// populated whenever a response is received from the server, including
+// for errors.
+func (ep *Endpoint[T]) Do(ctx context.Context, req *http.Request) (T, Response, error) {
+	r, err := ep.getWithResp(ctx, req)
+	resp := Response{
+		Bytes:    r.body,
+		Encoding: r.encoding,
+		When:     time.Now(),
+		Empty:    r.empty,
+		CacheHit: r.cacheHit,
+		Hedged:   r.hedged,
+		HedgeWon: r.hedgeWon,
+		Error:    err,
+	}
+	if r.resp != nil {
+		resp.FromHTTPResponse(r.resp)
+		resp.ContentEncoding, resp.CompressedSize = compressedSize(r.resp)
+	}
+	return r.value, resp, err
+}
+
+func (ep *Endpoint[T]) isSuccess(code int) bool {
+	if len(ep.successCodes) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, sc := range ep.successCodes {
+		if code == sc {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty returns true if the response cannot, or is known not to, have a
+// body.
+func isEmpty(req *http.Request, resp *http.Response) bool {
+	return req.Method == http.MethodHead ||
+		resp.StatusCode == http.StatusNoContent ||
+		resp.StatusCode == http.StatusResetContent ||
+		resp.ContentLength == 0
 }
 
 func (ep *Endpoint[T]) isBackoffCode(code int) bool {
@@ -100,79 +171,276 @@ func (ep *Endpoint[T]) isErrorRetryable(err error) (string, bool) {
 
 func (ep *Endpoint[T]) isErrorRetryableAndLog(ctx context.Context, req *http.Request, err error) bool {
 	msg, retryable := ep.isErrorRetryable(err)
-	grp := slog.Group("req", "url", req.URL, "err", err, "retryable", retryable)
+	grp := slog.Group("req", "url", ep.redactedURL(req.URL), "err", err, "retryable", retryable)
 	ctxlog.Info(ctx, msg, grp)
 	return retryable
 }
 
 func (ep *Endpoint[T]) logBackoff(ctx context.Context, msg string, req *http.Request, retries int, took time.Duration, done bool, err error) {
-	grp := slog.Group("req", "url", req.URL, "retries", retries, "took", took, "done", done, "err", err)
+	grp := slog.Group("req", "url", ep.redactedURL(req.URL), "retries", retries, "took", took, "done", done, "err", err)
 	ctxlog.Info(ctx, msg, grp)
 }
 
-func (ep *Endpoint[T]) getWithResp(ctx context.Context, req *http.Request) (T, *http.Response, []byte, error) {
-	var result T
-	if err := ep.rateController.Wait(ctx); err != nil {
-		return result, nil, nil, err
+func (ep *Endpoint[T]) getWithResp(ctx context.Context, req *http.Request) (result[T], error) {
+	ctx, span := ep.startRequestSpan(ctx, req)
+	stats := requestStats{span: span}
+	start := time.Now()
+	res, err := ep.issue(ctx, req, &stats)
+	res.hedged, res.hedgeWon = stats.hedged, stats.hedgeWon
+	ep.annotateError(req, stats.history, err)
+	ep.recordRequest(req, res, start, stats, err)
+	endRequestSpan(span, res.resp, stats, err)
+	return res, err
+}
+
+func (ep *Endpoint[T]) issue(ctx context.Context, req *http.Request, stats *requestStats) (result[T], error) {
+	var cached CachedResponse
+	var isCached bool
+	if ep.responseCache != nil {
+		cached, isCached = ep.conditional(ctx, req)
+	}
+	resp, retries, err := ep.do(ctx, req, stats)
+	if err != nil {
+		return result[T]{encoding: ep.encoding}, err
+	}
+	if isCached && resp.StatusCode == http.StatusNotModified {
+		return ep.fromCache(resp, cached, retries)
+	}
+	if !ep.isSuccess(resp.StatusCode) {
+		return ep.handleErrorResponse(resp, retries)
+	}
+	res, err := ep.handleResponse(ctx, req, resp, retries)
+	if err == nil && ep.responseCache != nil {
+		ep.cacheResult(ctx, req, res)
+	}
+	return res, err
+}
+
+// do issues the request, retrying as per the rate controller's backoff
+// policy, until a response with a status code that does not require
+// backoff is received. The body of the returned response has not been read.
+// The number of attempts made and the time spent backing off are recorded
+// in stats.
+func (ep *Endpoint[T]) do(ctx context.Context, req *http.Request, stats *requestStats) (*http.Response, int, error) {
+	rateController := ep.rateControllerFor(req)
+	if err := rateController.Wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	if err := ep.setIdempotencyKey(req); err != nil {
+		return nil, 0, err
+	}
+	if len(ep.accept) > 0 && len(req.Header.Get("Accept")) == 0 {
+		req.Header.Set("Accept", ep.accept)
 	}
-	backoff := ep.rateController.Backoff()
+	if len(ep.acceptEncoding) > 0 && len(req.Header.Get("Accept-Encoding")) == 0 {
+		req.Header.Set("Accept-Encoding", ep.acceptEncoding)
+	}
+	adaptive := ep.adaptiveRateControllerFor(req)
+	backoff := rateController.Backoff()
 	start := time.Now()
-	authSet := false
-	for {
+	authSet, reauthorized := false, false
+	var preAuth authState
+	for attempt := 0; ; attempt++ {
 		select {
 		case <-ctx.Done():
-			return result, nil, nil, ctx.Err()
+			return nil, 0, ctx.Err()
 		default:
 		}
 		retries := backoff.Retries()
-		var m T
+		stats.attempts = attempt + 1
+		if attempt > 0 {
+			if err := ep.rewindBody(req); err != nil {
+				return nil, retries, handleError(err, "", 0, retries)
+			}
+		}
+		if authSet && ep.resignEachAttempt() {
+			preAuth.reset(req)
+			authSet = false
+		}
 		if !authSet && ep.auth != nil {
-			if err := ep.auth.WithAuthorization(ctx, req); err != nil {
-				return m, nil, nil, handleError(err, "", 0, retries)
+			if attempt == 0 {
+				preAuth = newAuthState(req)
+			}
+			actx, span := StartSpan(ctx, SpanAuth)
+			err := ep.auth.WithAuthorization(actx, req)
+			endSpan(span, err)
+			if err != nil {
+				return nil, retries, handleError(err, "", 0, retries)
 			}
 			authSet = true
 		}
-		resp, err := http.DefaultClient.Do(req)
+		// Wait for the adaptive rate controller before consulting the
+		// circuit breaker since every request allowed by the latter must
+		// be recorded.
+		if adaptive != nil {
+			if err := adaptive.Wait(ctx); err != nil {
+				return nil, retries, err
+			}
+		}
+		if ep.circuitBreakers != nil {
+			if err := ep.circuitBreakers.allow(req.URL.Host); err != nil {
+				return nil, retries, handleError(err, "", 0, retries)
+			}
+		}
+		treq := req
+		if ep.httpTrace && stats.span != nil {
+			treq = withHTTPTrace(req, stats.span)
+		}
+		attemptStart := time.Now()
+		resp, err := ep.sendAttempt(ctx, treq, attempt, rateController, stats)
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			// The http.Client includes the URL in its errors.
+			uerr.URL = ep.redactedURL(req.URL)
+		}
+		stats.history = append(stats.history, Attempt{
+			When:       attemptStart,
+			Duration:   time.Since(attemptStart),
+			StatusCode: statusCode(resp),
+			Err:        err,
+		})
+		if ep.circuitBreakers != nil {
+			ep.circuitBreakers.record(req.URL.Host, err, statusCode(resp))
+		}
 		if err != nil {
-			if !ep.isErrorRetryableAndLog(ctx, req, err) {
-				return result, nil, nil, handleError(err, "", 0, retries)
+			if !ep.isErrorRetryableAndLog(ctx, req, err) || !ep.isIdempotent(req) {
+				return nil, retries, handleError(networkError(err), "", 0, retries)
 			}
-			if done, _ := backoff.Wait(ctx, nil); done {
+			if done := stats.wait(ctx, backoff, nil); done {
 				ep.logBackoff(ctx, "network backoff giving up", req, retries, time.Since(start), true, err)
-				return result, nil, nil, handleError(err, "", 0, retries)
+				return nil, retries, handleError(networkError(err), "", 0, retries)
 			}
 			ep.logBackoff(ctx, "network backoff", req, retries, time.Since(start), false, err)
 			continue
 		}
+		if adaptive != nil {
+			adaptive.Observe(ctx, resp)
+		}
+		if err := ep.decodeResponse(resp); err != nil {
+			resp.Body.Close()
+			return nil, retries, handleError(err, resp.Status, resp.StatusCode, retries)
+		}
+		if ep.auth != nil && !reauthorized && ep.shouldReauthorize(ctx, resp) {
+			// The credentials were rejected, re-authorize the request
+			// and retry it once.
+			readErrorBody(resp.Body)
+			ctxlog.Info(ctx, "re-authorizing request", slog.Group("req", "url", ep.redactedURL(req.URL), "status", resp.StatusCode))
+			preAuth.reset(req)
+			authSet, reauthorized = false, true
+			continue
+		}
 		if ep.isBackoffCode(resp.StatusCode) {
-			if done, _ := backoff.Wait(ctx, resp); done {
+			// The backoff policy is always consulted so that every
+			// retry counts against its budget, any delay requested by
+			// the server is then honoured in place of the backoff's
+			// own delay by waiting only for whatever remains of it.
+			body := readErrorBody(resp.Body)
+			waitStart := time.Now()
+			if done := stats.wait(ctx, backoff, resp); done {
 				ep.logBackoff(ctx, "application backoff giving up", req, retries, time.Since(start), true, err)
-				return result, nil, nil, handleError(err, resp.Status, resp.StatusCode, retries)
+				return nil, retries, errorWithBody(handleError(err, resp.Status, resp.StatusCode, retries), body)
+			}
+			serverStart := time.Now()
+			err := ep.waitForServer(ctx, req, resp, waitStart)
+			stats.backoff += time.Since(serverStart)
+			if err != nil {
+				return nil, retries, errorWithBody(handleError(err, resp.Status, resp.StatusCode, retries), body)
 			}
 			ep.logBackoff(ctx, "application backoff", req, retries, time.Since(start), false, err)
 			continue
 		}
-		if resp.StatusCode == http.StatusOK {
-			return ep.handleResponse(resp, retries)
-		}
-		return ep.handleErrorResponse(resp, retries)
+		return resp, retries, nil
+	}
+}
+
+// wait waits on the backoff policy and records the time spent doing so.
+func (s *requestStats) wait(ctx context.Context, backoff ratecontrol.Backoff, resp *http.Response) bool {
+	ctx, span := StartSpan(ctx, SpanBackoff, Attr("webapi.backoff.retries", backoff.Retries()))
+	start := time.Now()
+	done, err := backoff.Wait(ctx, resp)
+	s.backoff += time.Since(start)
+	span.SetAttributes(Attr("webapi.backoff.done", done))
+	endSpan(span, err)
+	return done
+}
+
+// sendAttempt sends a single attempt of req via any configured Middleware.
+func (ep *Endpoint[T]) sendAttempt(ctx context.Context, req *http.Request, attempt int, rc *ratecontrol.Controller, stats *requestStats) (*http.Response, error) {
+	send := func(ctx context.Context, req *http.Request, _ int) (*http.Response, error) {
+		return ep.send(ctx, req, rc, stats)
 	}
+	if len(ep.middleware) == 0 {
+		return send(ctx, req, attempt)
+	}
+	return chainMiddleware(ep.middleware, send)(ctx, req, attempt)
 }
 
-func (ep *Endpoint[T]) handleErrorResponse(resp *http.Response, steps int) (T, *http.Response, []byte, error) {
-	var result T
-	body, _ := io.ReadAll(resp.Body)
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func (ep *Endpoint[T]) handleErrorResponse(resp *http.Response, steps int) (result[T], error) {
+	body, _ := io.ReadAll(ep.limitBody(resp.Body))
 	resp.Body.Close()
-	return result, resp, body, handleError(nil, resp.Status, resp.StatusCode, steps)
+	return result[T]{resp: resp, body: body, encoding: ep.encoding},
+		errorWithBody(&Error{Status: resp.Status, StatusCode: resp.StatusCode, Attempts: steps}, body)
+}
+
+// errorWithBody sets the Body of an *Error to, at most, the first
+// MaxErrorBodySize bytes of body.
+func errorWithBody(err error, body []byte) error {
+	if operr, ok := err.(*Error); ok {
+		operr.Body = bytes.Clone(body[:min(len(body), MaxErrorBodySize)])
+	}
+	return err
 }
 
-func (ep *Endpoint[T]) handleResponse(resp *http.Response, steps int) (T, *http.Response, []byte, error) {
-	var result T
-	body, err := io.ReadAll(resp.Body)
+func (ep *Endpoint[T]) handleResponse(ctx context.Context, req *http.Request, resp *http.Response, steps int) (result[T], error) {
+	defer resp.Body.Close()
+	res := result[T]{resp: resp, encoding: ep.encoding}
+	if isEmpty(req, resp) {
+		res.empty = true
+		return res, nil
+	}
+	rd, store, err := ep.responseReader(ctx, req, resp)
 	if err != nil {
-		return result, resp, body, handleError(err, resp.Status, resp.StatusCode, steps)
+		return res, handleError(err, resp.Status, resp.StatusCode, steps)
 	}
-	resp.Body.Close()
-	err = ep.unmarshal(body, &result)
-	return result, resp, body, handleError(err, resp.Status, resp.StatusCode, steps)
+	if ep.streamUnmarshal != nil {
+		err = ep.streamUnmarshal(rd, &res.value)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrDecode, err)
+		}
+		if err == nil && store != nil {
+			// Make sure that the entire body is written to the store.
+			_, err = io.Copy(io.Discard, rd)
+		}
+	} else {
+		unmarshal := ep.unmarshal
+		if ep.autoEncoding {
+			res.encoding = EncodingForResponse(resp)
+			unmarshal = res.encoding.Unmarshal
+		}
+		res.body, err = io.ReadAll(rd)
+		if err == nil {
+			if err = unmarshal(res.body, &res.value); err != nil {
+				err = fmt.Errorf("%w: %w", ErrDecode, err)
+			}
+		}
+	}
+	if store != nil {
+		if cerr := store.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		// The status code may not be a 2xx code if configured as a
+		// success code via WithSuccessCodes, hence handleError is only
+		// called for errors.
+		return res, handleError(err, resp.Status, resp.StatusCode, steps)
+	}
+	return res, nil
 }
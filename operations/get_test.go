@@ -8,8 +8,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -181,3 +185,338 @@ func TestTimeout(t *testing.T) {
 		t.Errorf("got %v, want %v", got, want)
 	}
 }
+
+type countingTransport struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.mu.Lock()
+	ct.count++
+	ct.mu.Unlock()
+	req.Header.Set("User-Agent", "counting-transport")
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHTTPClient(t *testing.T) {
+	ctx := context.Background()
+	handler := webapitestutil.NewHeaderEchoHandler()
+	srv := webapitestutil.NewServer(handler)
+	defer srv.Close()
+
+	rt := &countingTransport{}
+	for _, opt := range []operations.Option{
+		operations.WithTransport(rt),
+		operations.WithHTTPClient(&http.Client{Transport: rt}),
+	} {
+		client := operations.NewEndpoint[map[string][]string](opt)
+		headers, _, _, err := client.Get(ctx, srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := headers["User-Agent"], []string{"counting-transport"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	if got, want := rt.count, 2; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 1))
+	client := operations.NewEndpoint[example](
+		operations.WithRateController(rc),
+		operations.WithHTTPClient(&http.Client{Timeout: time.Millisecond}))
+	slow := webapitestutil.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer slow.Close()
+	if _, _, _, err := client.Get(ctx, slow.URL); err == nil {
+		t.Errorf("expected a timeout error")
+	}
+}
+
+func TestPostRetries(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var bodies []example
+	var keys []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var eg example
+		if err := json.NewDecoder(r.Body).Decode(&eg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		bodies = append(bodies, eg)
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(eg)
+	})
+	srv := webapitestutil.NewServer(handler)
+	defer srv.Close()
+
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 5))
+	client := operations.NewEndpoint[example](
+		operations.WithRateController(rc, http.StatusTooManyRequests),
+		operations.WithIdempotencyKey("Idempotency-Key", nil))
+
+	eg := example{"foo", 42}
+	got, _, _, err := client.Post(ctx, srv.URL, eg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := got, eg; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := bodies, []example{eg, eg, eg}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if len(keys[0]) != 32 {
+		t.Errorf("missing or malformed idempotency key: %q", keys[0])
+	}
+	for _, k := range keys[1:] {
+		if got, want := k, keys[0]; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPostNetworkRetries(t *testing.T) {
+	ctx := context.Background()
+	slow := webapitestutil.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	for _, tc := range []struct {
+		opts     []operations.Option
+		attempts int
+	}{
+		{nil, 1},
+		{[]operations.Option{operations.WithIdempotencyKey("Idempotency-Key", func() string { return "key" })}, 3},
+	} {
+		rt := &countingTransport{}
+		rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 2))
+		opts := append([]operations.Option{
+			operations.WithRateController(rc),
+			operations.WithHTTPClient(&http.Client{Transport: rt, Timeout: time.Millisecond})},
+			tc.opts...)
+		client := operations.NewEndpoint[example](opts...)
+		if _, _, _, err := client.Post(ctx, slow.URL, example{"foo", 42}); err == nil {
+			t.Errorf("expected a timeout error")
+		}
+		if got, want := rt.count, tc.attempts; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSuccessCodes(t *testing.T) {
+	ctx := context.Background()
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(example{"created", 1})
+		case "/nocontent":
+			w.WriteHeader(http.StatusNoContent)
+		case "/notmodified":
+			w.WriteHeader(http.StatusNotModified)
+		case "/conflict":
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(example{"conflict", 2})
+		default:
+			_ = json.NewEncoder(w).Encode(example{"ok", 0})
+		}
+	}))
+	defer srv.Close()
+
+	client := operations.NewEndpoint[example]()
+	for _, tc := range []struct {
+		method, path string
+		code         int
+		empty        bool
+		val          example
+	}{
+		{http.MethodPost, "/created", http.StatusCreated, false, example{"created", 1}},
+		{http.MethodDelete, "/nocontent", http.StatusNoContent, true, example{}},
+		{http.MethodHead, "/", http.StatusOK, true, example{}},
+	} {
+		req, _ := http.NewRequestWithContext(ctx, tc.method, srv.URL+tc.path, nil)
+		val, resp, err := client.Do(ctx, req)
+		if err != nil {
+			t.Fatalf("%v %v: %v", tc.method, tc.path, err)
+		}
+		if got, want := resp.StatusCode, tc.code; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if got, want := resp.Empty, tc.empty; got != want {
+			t.Errorf("%v %v: got %v, want %v", tc.method, tc.path, got, want)
+		}
+		if got, want := val, tc.val; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+
+	client = operations.NewEndpoint[example](operations.WithSuccessCodes(http.StatusOK))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/created", nil)
+	_, resp, err := client.Do(ctx, req)
+	var operr *operations.Error
+	if !errors.As(err, &operr) || operr.StatusCode != http.StatusCreated {
+		t.Errorf("unexpected or missing error: %v", err)
+	}
+	if got, want := resp.StatusCode, http.StatusCreated; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Non-2xx status codes may be specified as indicating success.
+	client = operations.NewEndpoint[example](operations.WithSuccessCodes(http.StatusNotModified, http.StatusConflict))
+	for _, tc := range []struct {
+		path  string
+		code  int
+		empty bool
+		val   example
+	}{
+		{"/notmodified", http.StatusNotModified, true, example{}},
+		{"/conflict", http.StatusConflict, false, example{"conflict", 2}},
+	} {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+tc.path, nil)
+		val, resp, err := client.Do(ctx, req)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.path, err)
+		}
+		if got, want := resp.StatusCode, tc.code; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if got, want := resp.Empty, tc.empty; got != want {
+			t.Errorf("%v: got %v, want %v", tc.path, got, want)
+		}
+		if got, want := val, tc.val; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestErrorTaxonomy(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	for path, code := range map[string]int{
+		"/unauthorized": http.StatusUnauthorized,
+		"/forbidden":    http.StatusForbidden,
+		"/missing":      http.StatusNotFound,
+		"/server":       http.StatusInternalServerError,
+		"/busy":         http.StatusTooManyRequests,
+	} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(code)
+			fmt.Fprintf(w, "error: %v", code)
+		})
+	}
+	mux.HandleFunc("/large", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(bytes.Repeat([]byte{'x'}, operations.MaxErrorBodySize*2))
+	})
+	mux.HandleFunc("/decode", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("{not json"))
+	})
+	srv := webapitestutil.NewServer(mux)
+	defer srv.Close()
+
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 2))
+	ep := operations.NewEndpoint[example](operations.WithRateController(rc, http.StatusTooManyRequests))
+
+	for _, tc := range []struct {
+		path     string
+		sentinel error
+		action   operations.ErrorAction
+		attempts int
+	}{
+		{"/unauthorized", operations.ErrUnauthorized, operations.Abort, 1},
+		{"/forbidden", operations.ErrForbidden, operations.Skip, 1},
+		{"/missing", operations.ErrNotFound, operations.Skip, 1},
+		{"/server", operations.ErrServerError, operations.RetryLater, 1},
+		{"/busy", operations.ErrRateLimited, operations.RetryLater, 3},
+		{"/decode", operations.ErrDecode, operations.Skip, 1},
+	} {
+		_, _, _, err := ep.Get(ctx, srv.URL+tc.path+"?q=1")
+		if !errors.Is(err, tc.sentinel) {
+			t.Errorf("%v: %v is not %v", tc.path, err, tc.sentinel)
+		}
+		if got, want := operations.ActionForError(err), tc.action; got != want {
+			t.Errorf("%v: got %v, want %v", tc.path, got, want)
+		}
+		var operr *operations.Error
+		if !errors.As(err, &operr) {
+			t.Errorf("%v: not an *operations.Error: %v", tc.path, err)
+			continue
+		}
+		if got, want := operr.URL, srv.URL+tc.path+"?q=1"; got != want {
+			t.Errorf("%v: got %v, want %v", tc.path, got, want)
+		}
+		if got, want := operr.Method, http.MethodGet; got != want {
+			t.Errorf("%v: got %v, want %v", tc.path, got, want)
+		}
+		if got, want := len(operr.History), tc.attempts; got != want {
+			t.Errorf("%v: got %v, want %v", tc.path, got, want)
+		}
+		if tc.sentinel == operations.ErrDecode {
+			continue
+		}
+		if got, want := string(operr.Body), fmt.Sprintf("error: %v", operr.StatusCode); got != want {
+			t.Errorf("%v: got %v, want %v", tc.path, got, want)
+		}
+		for _, a := range operr.History {
+			if got, want := a.StatusCode, operr.StatusCode; got != want {
+				t.Errorf("%v: got %v, want %v", tc.path, got, want)
+			}
+		}
+	}
+
+	_, _, _, err := ep.Get(ctx, srv.URL+"/large")
+	var operr *operations.Error
+	if !errors.As(err, &operr) {
+		t.Fatalf("not an *operations.Error: %v", err)
+	}
+	if got, want := len(operr.Body), operations.MaxErrorBodySize; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := operations.ActionForError(err), operations.Skip; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	closed := webapitestutil.NewServer(mux)
+	closed.Close()
+	_, _, _, err = ep.Get(ctx, closed.URL)
+	if !errors.Is(err, operations.ErrNetwork) {
+		t.Errorf("%v is not %v", err, operations.ErrNetwork)
+	}
+	if got, want := operations.ActionForError(err), operations.RetryLater; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestErrorURLRedaction(t *testing.T) {
+	ctx := context.Background()
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	ep := operations.NewEndpoint[example](operations.WithRedactions("sig"))
+	_, _, _, err := ep.Get(ctx, srv.URL+"?api_key=secret1&sig=secret2&q=visible")
+	var operr *operations.Error
+	if !errors.As(err, &operr) {
+		t.Fatalf("not an *operations.Error: %v", err)
+	}
+	if got, want := operr.URL, srv.URL+"?api_key=REDACTED&q=visible&sig=REDACTED"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("secret not redacted: %v", err)
+	}
+}
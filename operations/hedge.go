@@ -0,0 +1,255 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloudeng.io/net/ratecontrol"
+)
+
+// HedgingConfig represents the configuration for hedged requests, see
+// WithHedging.
+type HedgingConfig struct {
+	// Delay is the time to wait for a response before sending a hedged
+	// request. It is used until enough latency samples have been collected
+	// to compute Percentile, or if Percentile is not set.
+	Delay time.Duration
+	// Percentile, if set, eg. to 0.95, specifies that the delay is to be
+	// the specified percentile of the latencies of recent requests, ie.
+	// the time taken to receive their response headers.
+	Percentile float64
+	// MinSamples is the number of latency samples required before
+	// Percentile is used, the default is 20.
+	MinSamples int
+	// Window is the number of recent latency samples used to compute
+	// Percentile, the default is 100.
+	Window int
+}
+
+// WithHedging specifies that GET and HEAD requests without a body are to
+// be hedged: if a response has not been received within the delay
+// specified by cfg, a duplicate request is sent and whichever response is
+// received first is used, the other request is canceled. The duplicate
+// request waits on the Endpoint's rate controller(s) before being sent
+// and hence stays within the rate limit budget; it is not sent if the
+// original request completes while it is waiting. Latency samples used
+// for percentile based delays are shared by all Endpoints created using
+// the same Option. Response.Hedged and Response.HedgeWon record whether a
+// hedged request was sent and whether it won.
+func WithHedging(cfg HedgingConfig) Option {
+	h := newHedger(cfg)
+	return func(o *options) {
+		o.hedger = h
+	}
+}
+
+type hedger struct {
+	cfg HedgingConfig
+
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func newHedger(cfg HedgingConfig) *hedger {
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = 20
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 100
+	}
+	cfg.Window = max(cfg.Window, cfg.MinSamples)
+	return &hedger{cfg: cfg}
+}
+
+// delay returns the delay after which a hedged request should be sent,
+// or false if hedging is not possible.
+func (h *hedger) delay() (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cfg.Percentile <= 0 || len(h.samples) < h.cfg.MinSamples {
+		return h.cfg.Delay, h.cfg.Delay > 0
+	}
+	sorted := slices.Sorted(slices.Values(h.samples))
+	idx := min(int(h.cfg.Percentile*float64(len(sorted))), len(sorted)-1)
+	return sorted[idx], true
+}
+
+func (h *hedger) record(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.samples) < h.cfg.Window {
+		h.samples = append(h.samples, latency)
+		return
+	}
+	h.samples[h.next] = latency
+	h.next = (h.next + 1) % len(h.samples)
+}
+
+func canHedge(req *http.Request) bool {
+	return (req.Method == "" || req.Method == http.MethodGet || req.Method == http.MethodHead) &&
+		(req.Body == nil || req.Body == http.NoBody)
+}
+
+type hedgeResult struct {
+	resp    *http.Response
+	err     error
+	hedge   bool
+	latency time.Duration
+}
+
+// cancelOnClose cancels the context of the winning request once its
+// body has been closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// send sends a single attempt of req, hedging it if so configured. Whether
+// a hedged request was sent, and whether it won, is recorded in stats.
+func (ep *Endpoint[T]) send(ctx context.Context, req *http.Request, rc *ratecontrol.Controller, stats *requestStats) (*http.Response, error) {
+	stats.hedged, stats.hedgeWon = false, false
+	if ep.hedger == nil || !canHedge(req) {
+		return ep.client.Do(req)
+	}
+	delay, ok := ep.hedger.delay()
+	if !ok {
+		start := time.Now()
+		resp, err := ep.client.Do(req)
+		if err == nil {
+			ep.hedger.record(time.Since(start))
+		}
+		return resp, err
+	}
+	return ep.hedgedDo(ctx, req, rc, delay, stats)
+}
+
+func (ep *Endpoint[T]) hedgedDo(ctx context.Context, req *http.Request, rc *ratecontrol.Controller, delay time.Duration, stats *requestStats) (*http.Response, error) {
+	results := make(chan hedgeResult, 2)
+	// Latencies are measured from when the original request was sent so
+	// that they reflect the latency of the original request even when it
+	// loses to the hedged request, otherwise only the latencies of the
+	// faster, hedged, requests would be recorded and the percentile
+	// would drift downwards.
+	start := time.Now()
+	issue := func(rctx context.Context, hedge bool) {
+		resp, err := ep.client.Do(req.Clone(rctx))
+		results <- hedgeResult{resp: resp, err: err, hedge: hedge, latency: time.Since(start)}
+	}
+	primaryCtx, cancelPrimary := context.WithCancel(req.Context())
+	go issue(primaryCtx, false)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case r := <-results:
+		return ep.hedgeWinner(r, cancelPrimary, stats)
+	case <-ctx.Done():
+		cancelPrimary()
+		go drainHedged(results, 1)
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	var sent atomic.Bool
+	defer func() { stats.hedged = sent.Load() }()
+	hedgeCtx, cancelHedgeReq := context.WithCancel(req.Context())
+	waitCtx, cancelWait := context.WithCancel(ctx)
+	cancelHedge := func() {
+		cancelWait()
+		cancelHedgeReq()
+	}
+	go func() {
+		// The hedged request must wait for the rate controllers as
+		// for any other request.
+		if err := rc.Wait(waitCtx); err != nil {
+			results <- hedgeResult{err: err, hedge: true}
+			return
+		}
+		if adaptive := ep.adaptiveRateControllerFor(req); adaptive != nil {
+			if err := adaptive.Wait(waitCtx); err != nil {
+				results <- hedgeResult{err: err, hedge: true}
+				return
+			}
+		}
+		sent.Store(true)
+		issue(hedgeCtx, true)
+	}()
+
+	var failed []hedgeResult
+	for outstanding := 2; outstanding > 0; {
+		select {
+		case r := <-results:
+			outstanding--
+			if r.err != nil {
+				failed = append(failed, r)
+				continue
+			}
+			// Cancel the loser and close its response body should
+			// it be received.
+			cancel := cancelPrimary
+			if r.hedge {
+				cancelPrimary()
+				cancel = cancelHedge
+			} else {
+				cancelHedge()
+			}
+			if outstanding > 0 {
+				go drainHedged(results, outstanding)
+			}
+			return ep.hedgeWinner(r, cancel, stats)
+		case <-ctx.Done():
+			cancelPrimary()
+			cancelHedge()
+			go drainHedged(results, outstanding)
+			return nil, ctx.Err()
+		}
+	}
+	cancelPrimary()
+	cancelHedge()
+	// Prefer the error from the original request.
+	for _, r := range failed {
+		if !r.hedge {
+			return nil, r.err
+		}
+	}
+	return nil, failed[0].err
+}
+
+// hedgeWinner returns the response for the winning request, the
+// request's context is canceled once the response body is closed.
+func (ep *Endpoint[T]) hedgeWinner(r hedgeResult, cancel context.CancelFunc, stats *requestStats) (*http.Response, error) {
+	if r.err != nil {
+		cancel()
+		return nil, r.err
+	}
+	stats.hedgeWon = r.hedge
+	ep.hedger.record(r.latency)
+	r.resp.Body = &cancelOnClose{ReadCloser: r.resp.Body, cancel: cancel}
+	return r.resp, nil
+}
+
+// drainHedged closes the bodies of any responses received for requests
+// that lost the race.
+func drainHedged(results <-chan hedgeResult, outstanding int) {
+	for range outstanding {
+		if r := <-results; r.resp != nil {
+			r.resp.Body.Close()
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+// slowFirstHandler delays its response to the first request, or to all
+// requests if always is set, until the request is canceled or delay has
+// elapsed.
+type slowFirstHandler struct {
+	mu       sync.Mutex
+	delay    time.Duration
+	always   bool
+	calls    int
+	canceled int
+}
+
+func (h *slowFirstHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.calls++
+	slow := h.calls == 1 || h.always
+	h.mu.Unlock()
+	if slow {
+		select {
+		case <-r.Context().Done():
+			h.mu.Lock()
+			h.canceled++
+			h.mu.Unlock()
+			return
+		case <-time.After(h.delay):
+		}
+	}
+	_, _ = w.Write([]byte(`{"Name":"foo","Value":42}`))
+}
+
+func (h *slowFirstHandler) stats() (calls, canceled int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls, h.canceled
+}
+
+func TestHedging(t *testing.T) {
+	ctx := context.Background()
+	handler := &slowFirstHandler{delay: 5 * time.Second}
+	srv := webapitestutil.NewServer(handler)
+	defer srv.Close()
+
+	ep := operations.NewEndpoint[example](operations.WithHedging(operations.HedgingConfig{Delay: 10 * time.Millisecond}))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	start := time.Now()
+	v, resp, err := ep.Do(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := time.Since(start), time.Second; got > want {
+		t.Errorf("got %v, want < %v", got, want)
+	}
+	if got, want := v.Value, 42; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !resp.Hedged || !resp.HedgeWon {
+		t.Errorf("expected a winning hedged request: %v, %v", resp.Hedged, resp.HedgeWon)
+	}
+	// The original request is canceled.
+	for {
+		if _, canceled := handler.stats(); canceled == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Fast responses are not hedged.
+	_, resp, err = ep.Do(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Hedged || resp.HedgeWon {
+		t.Errorf("unexpected hedged request: %v, %v", resp.Hedged, resp.HedgeWon)
+	}
+	if calls, _ := handler.stats(); calls != 3 {
+		t.Errorf("got %v, want %v", calls, 3)
+	}
+}
+
+func TestHedgingRateControl(t *testing.T) {
+	ctx := context.Background()
+	handler := &slowFirstHandler{delay: 100 * time.Millisecond}
+	srv := webapitestutil.NewServer(handler)
+	defer srv.Close()
+
+	// The hedged request must wait for the rate controller and is not
+	// sent since the original request completes first.
+	rc := ratecontrol.New(ratecontrol.WithRequestsPerTick(time.Second, 1))
+	ep := operations.NewEndpoint[example](
+		operations.WithRateController(rc),
+		operations.WithHedging(operations.HedgingConfig{Delay: 10 * time.Millisecond}))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	_, resp, err := ep.Do(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Hedged || resp.HedgeWon {
+		t.Errorf("unexpected hedged request: %v, %v", resp.Hedged, resp.HedgeWon)
+	}
+	if calls, _ := handler.stats(); calls != 1 {
+		t.Errorf("got %v, want %v", calls, 1)
+	}
+}
+
+func TestHedgingPercentile(t *testing.T) {
+	ctx := context.Background()
+	handler := &slowFirstHandler{delay: 50 * time.Millisecond}
+	srv := webapitestutil.NewServer(handler)
+	defer srv.Close()
+
+	// No delay is configured and hence the first request, which is slow,
+	// is not hedged, the second is hedged using the latency of the
+	// first as the delay.
+	opt := operations.WithHedging(operations.HedgingConfig{Percentile: 0.9, MinSamples: 1})
+	ep := operations.NewEndpoint[example](opt)
+	if _, resp, err := ep.Do(ctx, mustRequest(ctx, t, srv.URL)); err != nil || resp.Hedged {
+		t.Fatalf("unexpected hedged request or error: %v, %v", resp.Hedged, err)
+	}
+	handler.mu.Lock()
+	handler.always, handler.delay = true, 5*time.Second
+	handler.mu.Unlock()
+
+	// Latency samples are shared by Endpoints created with the same option.
+	ep = operations.NewEndpoint[example](opt)
+	cctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, resp, err := ep.Do(cctx, mustRequest(cctx, t, srv.URL))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !resp.Hedged {
+		t.Errorf("expected a hedged request")
+	}
+}
+
+func mustRequest(ctx context.Context, t *testing.T, url string) *http.Request {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// concurrentHandler responds slowly to requests received when no other
+// request is in progress, ie. to original requests, and immediately to
+// those received whilst another is in progress, ie. to hedged requests.
+// It records the delay between the arrival of each pair of requests.
+type concurrentHandler struct {
+	mu       sync.Mutex
+	inflight int
+	arrived  time.Time
+	gaps     []time.Duration
+}
+
+func (h *concurrentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.inflight++
+	slow := h.inflight == 1
+	if slow {
+		h.arrived = time.Now()
+	} else {
+		h.gaps = append(h.gaps, time.Since(h.arrived))
+	}
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		h.inflight--
+		h.mu.Unlock()
+	}()
+	if slow {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+	_, _ = w.Write([]byte(`{"Name":"foo","Value":42}`))
+}
+
+func TestHedgingPercentileDrift(t *testing.T) {
+	ctx := context.Background()
+	handler := &concurrentHandler{}
+	srv := webapitestutil.NewServer(handler)
+	defer srv.Close()
+
+	// The latency recorded for a request whose hedged request wins must
+	// include the delay before the hedged request was sent, otherwise
+	// the delay used for subsequent requests shrinks to the latency of
+	// the hedged requests alone.
+	delay := 20 * time.Millisecond
+	ep := operations.NewEndpoint[example](operations.WithHedging(operations.HedgingConfig{
+		Delay:      delay,
+		Percentile: 0.5,
+		MinSamples: 1,
+		Window:     1,
+	}))
+	for range 4 {
+		_, resp, err := ep.Do(ctx, mustRequest(ctx, t, srv.URL))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.HedgeWon {
+			t.Fatalf("expected the hedged request to win")
+		}
+		for {
+			handler.mu.Lock()
+			idle := handler.inflight == 0
+			handler.mu.Unlock()
+			if idle {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	for i, gap := range handler.gaps {
+		if gap < delay {
+			t.Errorf("request %v: hedged after %v, want >= %v", i, gap, delay)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricLabels identifies the client and endpoint that metrics are
+// recorded for.
+type MetricLabels struct {
+	Client   string
+	Endpoint string
+}
+
+// RequestMetrics describes a single request issued by an Endpoint,
+// including all of its retries.
+type RequestMetrics struct {
+	Method string
+	// StatusCode is the status code of the final response, it is zero
+	// if no response was received.
+	StatusCode int
+	// Failed is true if the request resulted in an error.
+	Failed bool
+	// Duration is the total time taken, including all retries and
+	// backoff delays.
+	Duration time.Duration
+	// Attempts is the number of times the request was sent.
+	Attempts int
+	// Backoff is the total time spent waiting before retrying the request.
+	Backoff time.Duration
+	// BytesSent and BytesReceived are the sizes of the request and
+	// response bodies. BytesReceived is the Content-Length of the
+	// response, if known, when the body is not read by the Endpoint,
+	// eg. when using Stream.
+	BytesSent, BytesReceived int64
+	// CompressedBytesReceived is the number of bytes of a compressed
+	// response body that were received, see WithCompression. It is
+	// zero if the response was not compressed or its body was not read
+	// by the Endpoint, eg. when using Stream.
+	CompressedBytesReceived int64
+}
+
+// Metrics is the interface used to record metrics for Endpoints, Scanners
+// and crawls. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Request is called once for every request issued by an Endpoint.
+	Request(MetricLabels, RequestMetrics)
+	// Pages is called for every page scanned by a Scanner.
+	Pages(MetricLabels, int)
+	// Objects is called for every batch of objects successfully handled
+	// by RunCrawl.
+	Objects(MetricLabels, int)
+}
+
+// WithMetrics specifies the Metrics instance to use to record metrics
+// for requests issued by an Endpoint, and for pages scanned by a Scanner,
+// using the supplied labels.
+func WithMetrics(m Metrics, labels MetricLabels) Option {
+	return func(o *options) {
+		o.metrics = m
+		o.metricLabels = labels
+	}
+}
+
+// WithCrawlMetrics specifies the Metrics instance to be used by RunCrawl
+// to record the number of objects crawled.
+func WithCrawlMetrics(m Metrics, labels MetricLabels) CrawlOption {
+	return func(o *crawlOptions) {
+		o.metrics = m
+		o.metricLabels = labels
+	}
+}
+
+// requestStats records statistics for a single request, including any
+// retries, as it is issued, along with the span used to trace it.
+type requestStats struct {
+	attempts int
+	backoff  time.Duration
+	span     Span
+	history  []Attempt
+	hedged   bool
+	hedgeWon bool
+}
+
+func (ep *Endpoint[T]) recordRequest(req *http.Request, res result[T], start time.Time, stats requestStats, err error) {
+	if ep.metrics == nil {
+		return
+	}
+	m := RequestMetrics{
+		Method:   req.Method,
+		Failed:   err != nil,
+		Duration: time.Since(start),
+		Attempts: stats.attempts,
+		Backoff:  stats.backoff,
+	}
+	if req.ContentLength > 0 {
+		m.BytesSent = req.ContentLength
+	}
+	if res.resp != nil {
+		m.StatusCode = res.resp.StatusCode
+		m.BytesReceived = int64(len(res.body))
+		switch {
+		case res.streamed:
+			m.BytesReceived = res.received
+		case res.body == nil && res.resp.ContentLength > 0:
+			m.BytesReceived = res.resp.ContentLength
+		}
+		_, m.CompressedBytesReceived = compressedSize(res.resp)
+	}
+	ep.metrics.Request(ep.metricLabels, m)
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the buckets
+// used for the request duration histogram maintained by MetricsRegistry.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type metricKey struct {
+	client, endpoint string
+	method, code     string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type counter struct {
+	name, help string
+	values     map[metricKey]float64
+}
+
+// MetricsRegistry is an in-memory implementation of Metrics that can
+// export the metrics it records in the Prometheus text exposition format,
+// see WritePrometheus and ServeHTTP, or via expvar, see Var.
+type MetricsRegistry struct {
+	mu        sync.Mutex
+	buckets   []float64
+	counters  []*counter
+	durations map[metricKey]*histogram
+
+	requests, errors, retries, backoff, sent, received, compressed, pages, objects *counter
+}
+
+// NewMetricsRegistry returns a new MetricsRegistry. If no buckets
+// are specified, DefaultLatencyBuckets are used.
+func NewMetricsRegistry(buckets ...float64) *MetricsRegistry {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	r := &MetricsRegistry{
+		buckets:   slices.Sorted(slices.Values(buckets)),
+		durations: map[metricKey]*histogram{},
+	}
+	r.requests = r.newCounter("webapi_requests_total", "Number of requests issued, by method and final status code.")
+	r.errors = r.newCounter("webapi_request_errors_total", "Number of requests that resulted in an error.")
+	r.retries = r.newCounter("webapi_request_retries_total", "Number of times that requests were retried.")
+	r.backoff = r.newCounter("webapi_request_backoff_seconds_total", "Time spent waiting to retry requests.")
+	r.sent = r.newCounter("webapi_request_bytes_sent_total", "Number of request body bytes sent.")
+	r.received = r.newCounter("webapi_response_bytes_received_total", "Number of response body bytes received.")
+	r.compressed = r.newCounter("webapi_response_compressed_bytes_received_total", "Number of compressed response body bytes received.")
+	r.pages = r.newCounter("webapi_pages_scanned_total", "Number of pages scanned.")
+	r.objects = r.newCounter("webapi_objects_crawled_total", "Number of objects crawled.")
+	return r
+}
+
+func (r *MetricsRegistry) newCounter(name, help string) *counter {
+	c := &counter{name: name, help: help, values: map[metricKey]float64{}}
+	r.counters = append(r.counters, c)
+	return c
+}
+
+// Request implements Metrics.
+func (r *MetricsRegistry) Request(labels MetricLabels, m RequestMetrics) {
+	key := metricKey{client: labels.Client, endpoint: labels.Endpoint}
+	code := "none"
+	if m.StatusCode != 0 {
+		code = strconv.Itoa(m.StatusCode)
+	}
+	method := m.Method
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests.values[metricKey{labels.Client, labels.Endpoint, method, code}]++
+	if m.Failed {
+		r.errors.values[key]++
+	}
+	if m.Attempts > 1 {
+		r.retries.values[key] += float64(m.Attempts - 1)
+	}
+	r.backoff.values[key] += m.Backoff.Seconds()
+	r.sent.values[key] += float64(m.BytesSent)
+	r.received.values[key] += float64(m.BytesReceived)
+	r.compressed.values[key] += float64(m.CompressedBytesReceived)
+	h := r.durations[key]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(r.buckets))}
+		r.durations[key] = h
+	}
+	secs := m.Duration.Seconds()
+	for i, b := range r.buckets {
+		if secs <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += secs
+	h.count++
+}
+
+// Pages implements Metrics.
+func (r *MetricsRegistry) Pages(labels MetricLabels, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pages.values[metricKey{client: labels.Client, endpoint: labels.Endpoint}] += float64(n)
+}
+
+// Objects implements Metrics.
+func (r *MetricsRegistry) Objects(labels MetricLabels, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.objects.values[metricKey{client: labels.Client, endpoint: labels.Endpoint}] += float64(n)
+}
+
+// Value returns the current value of the named counter for the
+// specified labels, summed over all methods and status codes where
+// applicable.
+func (r *MetricsRegistry) Value(name string, labels MetricLabels) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total float64
+	for _, c := range r.counters {
+		if c.name != name {
+			continue
+		}
+		for k, v := range c.values {
+			if k.client == labels.Client && k.endpoint == labels.Endpoint {
+				total += v
+			}
+		}
+	}
+	return total
+}
+
+func (k metricKey) labels(extra ...string) string {
+	pairs := []string{
+		"client=" + strconv.Quote(k.client),
+		"endpoint=" + strconv.Quote(k.endpoint),
+	}
+	if len(k.method) > 0 {
+		pairs = append(pairs, "method="+strconv.Quote(k.method), "code="+strconv.Quote(k.code))
+	}
+	pairs = append(pairs, extra...)
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func sortedKeys[V any](m map[metricKey]V) []metricKey {
+	keys := make([]metricKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b metricKey) int {
+		return strings.Compare(a.labels(), b.labels())
+	})
+	return keys
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WritePrometheus writes all of the metrics recorded so far to w in the
+// Prometheus text exposition format.
+func (r *MetricsRegistry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sb strings.Builder
+	for _, c := range r.counters {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+		for _, k := range sortedKeys(c.values) {
+			fmt.Fprintf(&sb, "%s%s %s\n", c.name, k.labels(), formatFloat(c.values[k]))
+		}
+	}
+	const name = "webapi_request_duration_seconds"
+	fmt.Fprintf(&sb, "# HELP %s Time taken by requests, including retries.\n# TYPE %s histogram\n", name, name)
+	for _, k := range sortedKeys(r.durations) {
+		h := r.durations[k]
+		for i, b := range r.buckets {
+			fmt.Fprintf(&sb, "%s_bucket%s %d\n", name, k.labels("le="+strconv.Quote(formatFloat(b))), h.counts[i])
+		}
+		fmt.Fprintf(&sb, "%s_bucket%s %d\n", name, k.labels(`le="+Inf"`), h.count)
+		fmt.Fprintf(&sb, "%s_sum%s %s\n", name, k.labels(), formatFloat(h.sum))
+		fmt.Fprintf(&sb, "%s_count%s %d\n", name, k.labels(), h.count)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// ServeHTTP implements http.Handler to serve the metrics in the
+// Prometheus text exposition format.
+func (r *MetricsRegistry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.WritePrometheus(w)
+}
+
+// Var returns an expvar.Var that can be used to publish the metrics
+// via expvar, eg. expvar.Publish("webapi", registry.Var()). The value is
+// a map of metric names to maps of label sets to values.
+func (r *MetricsRegistry) Var() expvar.Var {
+	return expvar.Func(func() any {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		out := map[string]map[string]float64{}
+		for _, c := range r.counters {
+			values := map[string]float64{}
+			for k, v := range c.values {
+				values[k.labels()] = v
+			}
+			out[c.name] = values
+		}
+		sums := map[string]float64{}
+		counts := map[string]float64{}
+		for k, h := range r.durations {
+			sums[k.labels()] = h.sum
+			counts[k.labels()] = float64(h.count)
+		}
+		out["webapi_request_duration_seconds_sum"] = sums
+		out["webapi_request_duration_seconds_count"] = counts
+		return out
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloudeng.io/file/content"
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(example{"foo", 42})
+	})
+	srv := webapitestutil.NewServer(handler)
+	defer srv.Close()
+
+	reg := operations.NewMetricsRegistry()
+	labels := operations.MetricLabels{Client: "test", Endpoint: "example"}
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 5))
+	ep := operations.NewEndpoint[example](
+		operations.WithRateController(rc, http.StatusTooManyRequests),
+		operations.WithMetrics(reg, labels))
+
+	if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := ep.Post(ctx, srv.URL, example{"bar", 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := ep.Get(ctx, srv.URL+"/missing"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	sent, _ := json.Marshal(example{"bar", 1})
+	received, _ := json.Marshal(example{"foo", 42})
+	for _, tc := range []struct {
+		name string
+		want float64
+	}{
+		{"webapi_requests_total", 3},
+		{"webapi_request_errors_total", 1},
+		{"webapi_request_retries_total", 1},
+		{"webapi_request_bytes_sent_total", float64(len(sent))},
+		{"webapi_response_bytes_received_total", float64(2 * (len(received) + 1))}, // json.Encoder appends a newline.
+	} {
+		if got, want := reg.Value(tc.name, labels), tc.want; got != want {
+			t.Errorf("%v: got %v, want %v", tc.name, got, want)
+		}
+	}
+	if got := reg.Value("webapi_request_backoff_seconds_total", labels); got <= 0 {
+		t.Errorf("expected non-zero backoff time: %v", got)
+	}
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE webapi_requests_total counter",
+		`webapi_requests_total{client="test",endpoint="example",method="GET",code="200"} 1`,
+		`webapi_requests_total{client="test",endpoint="example",method="GET",code="404"} 1`,
+		`webapi_requests_total{client="test",endpoint="example",method="POST",code="200"} 1`,
+		"# TYPE webapi_request_duration_seconds histogram",
+		`webapi_request_duration_seconds_bucket{client="test",endpoint="example",le="+Inf"} 3`,
+		`webapi_request_duration_seconds_count{client="test",endpoint="example"} 3`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+
+	var vars map[string]map[string]float64
+	if err := json.Unmarshal([]byte(reg.Var().String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := vars["webapi_request_errors_total"][`{client="test",endpoint="example"}`], 1.0; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCrawlMetrics(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	mux.Handle("/list", &webapitestutil.PaginatedHandler{
+		Last: 10,
+	})
+	mux.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Object{ID: r.URL.Query().Get("id")})
+	})
+	srv := webapitestutil.NewServer(mux)
+	defer srv.Close()
+
+	reg := operations.NewMetricsRegistry()
+	listLabels := operations.MetricLabels{Client: "test", Endpoint: "list"}
+	getLabels := operations.MetricLabels{Client: "test", Endpoint: "get"}
+	paginator := &paginator{url: srv.URL + "/list"}
+	scanner := operations.NewScanner[webapitestutil.Paginated](paginator, operations.WithMetrics(reg, listLabels))
+	fetcher := &fetcher{url: srv.URL, ep: operations.NewEndpoint[Object](operations.WithMetrics(reg, getLabels))}
+	cr := operations.NewCrawler[webapitestutil.Paginated, Object](scanner, fetcher)
+
+	err := operations.RunCrawl(ctx, cr, func(context.Context, []content.Object[Object, operations.Response]) error {
+		return nil
+	}, operations.WithCrawlMetrics(reg, getLabels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name   string
+		labels operations.MetricLabels
+		want   float64
+	}{
+		{"webapi_pages_scanned_total", listLabels, 11},
+		{"webapi_requests_total", listLabels, 11},
+		{"webapi_requests_total", getLabels, 11},
+		{"webapi_objects_crawled_total", getLabels, 11},
+	} {
+		if got, want := reg.Value(tc.name, tc.labels), tc.want; got != want {
+			t.Errorf("%v: %v: got %v, want %v", tc.name, tc.labels, got, want)
+		}
+	}
+}
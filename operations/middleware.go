@@ -0,0 +1,182 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloudeng.io/logging/ctxlog"
+)
+
+// AttemptFunc represents a single attempt at sending a request. Attempt
+// is zero for the first attempt and is incremented for each retry.
+type AttemptFunc func(ctx context.Context, req *http.Request, attempt int) (*http.Response, error)
+
+// Middleware wraps each attempt made by an Endpoint to send a request,
+// allowing for requests to be modified before they are sent and for
+// responses to be inspected or modified once they are received.
+// Middleware is called after the request has been authorized and any
+// rate control has been applied. Note that the same *http.Request is used
+// for all attempts and hence any modifications made to it are visible to
+// subsequent attempts.
+type Middleware func(next AttemptFunc) AttemptFunc
+
+// WithMiddleware adds to the Middleware used by an Endpoint. The first
+// Middleware registered is the outermost, ie. it is the first to see
+// each request and the last to see each response.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+func chainMiddleware(mw []Middleware, fn AttemptFunc) AttemptFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		fn = mw[i](fn)
+	}
+	return fn
+}
+
+// UserAgent returns Middleware that sets the User-Agent header for
+// requests that do not already specify one.
+func UserAgent(ua string) Middleware {
+	return Headers(http.Header{"User-Agent": {ua}})
+}
+
+// Headers returns Middleware that adds the specified headers to requests
+// that do not already specify them.
+func Headers(h http.Header) Middleware {
+	h = h.Clone()
+	return func(next AttemptFunc) AttemptFunc {
+		return func(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+			for k, v := range h {
+				if len(req.Header.Values(k)) == 0 {
+					req.Header[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+				}
+			}
+			return next(ctx, req, attempt)
+		}
+	}
+}
+
+// RequestID returns Middleware that sets the specified header, typically
+// X-Request-ID, to a unique ID for requests that do not already specify
+// one. The ID is generated by calling gen, or is a random 128 bit hex
+// string if gen is nil, and the same ID is used for all attempts.
+func RequestID(header string, gen func() string) Middleware {
+	if gen == nil {
+		gen = func() string {
+			var id [16]byte
+			_, _ = rand.Read(id[:])
+			return hex.EncodeToString(id[:])
+		}
+	}
+	return func(next AttemptFunc) AttemptFunc {
+		return func(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+			if len(req.Header.Get(header)) == 0 {
+				req.Header.Set(header, gen())
+			}
+			return next(ctx, req, attempt)
+		}
+	}
+}
+
+// DefaultRedactions are the headers, commonly used to carry credentials,
+// whose values are always redacted by Logging. They are the same as the
+// headers scrubbed by default from webapitestutil cassettes.
+var DefaultRedactions = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"Api-Key",
+	"X-Auth-Token",
+	"X-Amz-Security-Token",
+}
+
+// DefaultQueryRedactions are the URL query parameters, commonly used to
+// carry credentials, whose values are always redacted by Logging and from
+// the url.full attribute of request spans.
+var DefaultQueryRedactions = []string{"api_key", "apikey", "access_token", "token", "client_secret", "signature", "X-Amz-Signature", "X-Amz-Security-Token"}
+
+// Logging returns Middleware that logs each attempt, via ctxlog, including
+// the request's headers and the response's status code. The values of the
+// headers in DefaultRedactions, the query parameters in
+// DefaultQueryRedactions, and of any headers or URL query parameters named
+// in redact, are replaced by REDACTED, as is any password in the URL.
+func Logging(redact ...string) Middleware {
+	names := redactionSet(DefaultRedactions, DefaultQueryRedactions, redact)
+	return func(next AttemptFunc) AttemptFunc {
+		return func(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req, attempt)
+			attrs := []any{
+				"method", req.Method,
+				"url", redactURL(req.URL, names),
+				"attempt", attempt,
+				"headers", redactHeaders(req.Header, names),
+				"took", time.Since(start),
+			}
+			if resp != nil {
+				attrs = append(attrs, "status", resp.StatusCode)
+			}
+			if err != nil {
+				attrs = append(attrs, "err", err)
+			}
+			ctxlog.Info(ctx, "request attempt", slog.Group("req", attrs...))
+			return resp, err
+		}
+	}
+}
+
+const redacted = "REDACTED"
+
+// redactionSet returns the set of lowercased names to be redacted.
+func redactionSet(lists ...[]string) map[string]bool {
+	names := map[string]bool{}
+	for _, list := range lists {
+		for _, n := range list {
+			names[strings.ToLower(n)] = true
+		}
+	}
+	return names
+}
+
+func redactHeaders(h http.Header, names map[string]bool) http.Header {
+	r := make(http.Header, len(h))
+	for k, v := range h {
+		if names[strings.ToLower(k)] {
+			r[k] = []string{redacted}
+			continue
+		}
+		r[k] = v
+	}
+	return r
+}
+
+func redactURL(u *url.URL, names map[string]bool) string {
+	q := u.Query()
+	modified := false
+	for k := range q {
+		if names[strings.ToLower(k)] {
+			q.Set(k, redacted)
+			modified = true
+		}
+	}
+	if !modified {
+		return u.Redacted()
+	}
+	c := *u
+	c.RawQuery = q.Encode()
+	return c.Redacted()
+}
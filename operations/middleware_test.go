@@ -0,0 +1,157 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloudeng.io/logging/ctxlog"
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var headers []http.Header
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		headers = append(headers, r.Header.Clone())
+		if len(headers) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Name":"foo","Value":42}`))
+	}))
+	defer srv.Close()
+
+	var trace []string
+	record := func(name string) operations.Middleware {
+		return func(next operations.AttemptFunc) operations.AttemptFunc {
+			return func(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+				trace = append(trace, name+":req:"+strings.Repeat("r", attempt))
+				resp, err := next(ctx, req, attempt)
+				trace = append(trace, name+":resp:"+resp.Status[:3])
+				return resp, err
+			}
+		}
+	}
+	var logs bytes.Buffer
+	ctx := ctxlog.WithLogger(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)))
+
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 5))
+	ep := operations.NewEndpoint[example](
+		operations.WithRateController(rc, http.StatusTooManyRequests),
+		operations.WithAuth(&authToken{"secret-token"}),
+		operations.WithMiddleware(record("a"), record("b")),
+		operations.WithMiddleware(
+			operations.UserAgent("test-agent/1.0"),
+			operations.RequestID("X-Request-ID", func() string { return "req-1" }),
+			operations.Headers(http.Header{"X-Extra": {"extra"}}),
+			operations.Logging("api_key", "bearer"),
+		))
+	if _, _, _, err := ep.Get(ctx, srv.URL+"?api_key=12345&q=x"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(trace, " "), "a:req: b:req: b:resp:429 a:resp:429 a:req:r b:req:r b:resp:200 a:resp:200"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, h := range headers {
+		for k, v := range map[string]string{
+			"User-Agent":   "test-agent/1.0",
+			"X-Request-Id": "req-1",
+			"X-Extra":      "extra",
+		} {
+			if got, want := h.Get(k), v; got != want {
+				t.Errorf("%v: got %v, want %v", k, got, want)
+			}
+		}
+	}
+	var attempts []string
+	for line := range strings.Lines(logs.String()) {
+		if strings.Contains(line, "request attempt") {
+			attempts = append(attempts, line)
+		}
+	}
+	if got, want := len(attempts), 2; got != want {
+		t.Errorf("got %v, want %v: %s", got, want, logs.String())
+	}
+	out := strings.Join(attempts, "")
+	for _, secret := range []string{"secret-token", "12345"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output contains %q: %s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "api_key=REDACTED") {
+		t.Errorf("log output does not contain a redacted api_key: %s", out)
+	}
+}
+
+func TestMiddlewareRewrite(t *testing.T) {
+	ctx := context.Background()
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	// Rewrite 404 responses as empty successful responses.
+	notFoundAsEmpty := func(next operations.AttemptFunc) operations.AttemptFunc {
+		return func(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+			resp, err := next(ctx, req, attempt)
+			if err == nil && resp.StatusCode == http.StatusNotFound {
+				resp.StatusCode, resp.Status = http.StatusNoContent, "204 No Content"
+			}
+			return resp, err
+		}
+	}
+	ep := operations.NewEndpoint[example](operations.WithMiddleware(notFoundAsEmpty))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	_, resp, err := ep.Do(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resp.StatusCode, http.StatusNoContent; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !resp.Empty {
+		t.Errorf("expected an empty response")
+	}
+}
+
+func TestLoggingDefaultRedactions(t *testing.T) {
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(example{"foo", 42})
+	}))
+	defer srv.Close()
+	var logs bytes.Buffer
+	ctx := ctxlog.WithLogger(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)))
+
+	hdr := http.Header{
+		"X-Api-Key":    {"secret-1"},
+		"Api-Key":      {"secret-2"},
+		"X-Auth-Token": {"secret-3"},
+	}
+	ep := operations.NewEndpoint[example](
+		operations.WithMiddleware(operations.Headers(hdr), operations.Logging()))
+	if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), "request attempt") {
+		t.Fatalf("missing log output: %s", logs.String())
+	}
+	if strings.Contains(logs.String(), "secret-") {
+		t.Errorf("log output contains a secret: %s", logs.String())
+	}
+}
@@ -5,6 +5,13 @@
 package operations
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
 	"cloudeng.io/net/ratecontrol"
 )
 
@@ -13,11 +20,37 @@ import (
 type Option func(o *options)
 
 type options struct {
-	backoffStatusCodes []int
-	rateController     *ratecontrol.Controller
-	auth               Auth
-	unmarshal          Unmarshal
-	encoding           Encoding
+	backoffStatusCodes        []int
+	rateController            *ratecontrol.Controller
+	sharedRateControl         *sharedRateControl
+	adaptiveRateController    *AdaptiveRateController
+	sharedAdaptiveRateControl *sharedAdaptiveRateControl
+	auth                      Auth
+	unmarshal                 Unmarshal
+	encoding                  Encoding
+	autoEncoding              bool
+	accept                    string
+	client                    *http.Client
+	marshal                   Marshal
+	contentType               string
+	idempotencyHeader         string
+	idempotencyKey            func() string
+	maxResponseSize           int64
+	streamUnmarshal           StreamUnmarshal
+	bodyStore                 BodyStore
+	successCodes              []int
+	rateLimitHeaders          *RateLimitHeaders
+	circuitBreakers           *CircuitBreakers
+	responseCache             ResponseCache
+	prefetch                  int
+	metrics                   Metrics
+	metricLabels              MetricLabels
+	httpTrace                 bool
+	codecs                    map[string]Codec
+	acceptEncoding            string
+	hedger                    *hedger
+	middleware                []Middleware
+	redactions                []string
 }
 
 // WithRateController sets the rate controller to use to enforce rate
@@ -26,6 +59,20 @@ func WithRateController(rc *ratecontrol.Controller, statusCodes ...int) Option {
 	return func(o *options) {
 		o.backoffStatusCodes = statusCodes
 		o.rateController = rc
+		o.sharedRateControl = nil
+	}
+}
+
+// WithRateLimitHeaders specifies the headers used to determine the
+// server-specified delay to wait for before retrying a request that
+// was rejected with one of the status codes passed to WithRateController.
+// The server-specified delay is used in place of the rate controller's
+// backoff delay, unless the latter is longer, and each such retry counts
+// against the backoff's budget. The default is DefaultRateLimitHeaders,
+// a zero value RateLimitHeaders disables the use of such headers.
+func WithRateLimitHeaders(h RateLimitHeaders) Option {
+	return func(o *options) {
+		o.rateLimitHeaders = &h
 	}
 }
 
@@ -41,10 +88,161 @@ func WithAuth(a Auth) Option {
 type Unmarshal func([]byte, any) error
 
 // WithUnmarshal specifies a custom unmarshaling function to use for decoding
-// response bodies. The default is json.Unmarshal.
+// response bodies. The default is to select the Encoding, and hence the
+// unmarshaling function, to use for each response based on its
+// Content-Type, falling back to JSONEncoding, see EncodingForResponse.
 func WithUnmarshal(u Unmarshal, e Encoding) Option {
 	return func(o *options) {
 		o.unmarshal = u
 		o.encoding = e
 	}
 }
+
+// WithHTTPClient specifies the http.Client to use when making requests,
+// the default is http.DefaultClient. Use a custom client to configure
+// proxies, TLS settings (custom CA bundles, client certificates),
+// connection pool limits or a per-attempt timeout. Note that a client
+// timeout applies to each attempt rather than to all of the retries
+// made for a single request.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.client = c
+	}
+}
+
+// WithTransport specifies the http.RoundTripper to use when making
+// requests. It is a convenience for WithHTTPClient(&http.Client{Transport: rt})
+// and overrides any previous WithHTTPClient or WithTransport option.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) {
+		o.client = &http.Client{Transport: rt}
+	}
+}
+
+// Marshal represents a function that can be used to marshal a request
+// body.
+type Marshal func(any) ([]byte, error)
+
+// WithMarshal specifies a custom marshaling function, and the content type
+// of the data it produces, to use for encoding request bodies. The default
+// is json.Marshal and application/json.
+func WithMarshal(m Marshal, contentType string) Option {
+	return func(o *options) {
+		o.marshal = m
+		o.contentType = contentType
+	}
+}
+
+// WithIdempotencyKey specifies that requests using non-idempotent methods
+// (ie. POST and PATCH) are to be sent with an idempotency key in the
+// specified header (typically Idempotency-Key). The key is generated by
+// calling gen, or is a random 128 bit hex string if gen is nil, and the
+// same key is used for all retries of the same request. Requests that use
+// a non-idempotent method are only retried following a network error if
+// they carry an idempotency key. Note that a key will not be added to a
+// request that already has one.
+func WithIdempotencyKey(header string, gen func() string) Option {
+	return func(o *options) {
+		o.idempotencyHeader = header
+		o.idempotencyKey = gen
+	}
+}
+
+// WithMaxResponseSize specifies the maximum size, in bytes, of a response
+// body. Responses that exceed this size result in an error that wraps
+// ErrResponseTooLarge. The default is no limit.
+func WithMaxResponseSize(size int64) Option {
+	return func(o *options) {
+		o.maxResponseSize = size
+	}
+}
+
+// StreamUnmarshal represents a function that can be used to unmarshal a
+// response body as it is read.
+type StreamUnmarshal func(io.Reader, any) error
+
+// JSONStreamUnmarshal is a StreamUnmarshal function that uses a
+// json.Decoder to decode a single JSON value.
+func JSONStreamUnmarshal(rd io.Reader, v any) error {
+	return json.NewDecoder(rd).Decode(v)
+}
+
+// WithStreaming specifies that response bodies are to be decoded as
+// they are read using the supplied function rather than being read into
+// memory in their entirety and then unmarshaled. When streaming is enabled
+// the raw bytes of the response body are not returned by Get etc,
+// see WithBodyStore for a means of retaining them.
+func WithStreaming(u StreamUnmarshal, e Encoding) Option {
+	return func(o *options) {
+		o.streamUnmarshal = u
+		o.encoding = e
+	}
+}
+
+// BodyStore is used to store the raw bytes of response bodies as they are
+// read, typically to a file or content store.
+type BodyStore interface {
+	// Writer returns the io.WriteCloser to which the body of the supplied
+	// response, received for req, is to be written. The writer is closed once
+	// the body has been read.
+	Writer(ctx context.Context, req *http.Request, resp *http.Response) (io.WriteCloser, error)
+}
+
+// WithBodyStore specifies a BodyStore to which the raw bytes of all
+// successful response bodies are written as they are read. When used
+// with WithStreaming this allows for the raw bytes of large responses to be
+// retained without holding them in memory.
+func WithBodyStore(bs BodyStore) Option {
+	return func(o *options) {
+		o.bodyStore = bs
+	}
+}
+
+// WithAccept specifies the encodings, in order of preference, to be
+// requested via the Accept header. The header is only set for requests
+// that do not already specify one.
+func WithAccept(encs ...Encoding) Option {
+	return func(o *options) {
+		types := make([]string, 0, len(encs))
+		for i, e := range encs {
+			ct := e.ContentType()
+			if i > 0 {
+				ct = fmt.Sprintf("%s;q=%.1f", ct, max(0.1, 1-float64(i)/10))
+			}
+			types = append(types, ct)
+		}
+		o.accept = strings.Join(types, ", ")
+	}
+}
+
+// WithSuccessCodes specifies the HTTP status codes that are considered
+// to indicate success, all other status codes result in an error. The
+// default is to treat all 2xx status codes as success.
+func WithSuccessCodes(codes ...int) Option {
+	return func(o *options) {
+		o.successCodes = codes
+	}
+}
+
+// WithCircuitBreakers specifies the set of per-host circuit breakers to
+// use. Requests to a host whose circuit breaker is open fail immediately
+// with an error that wraps ErrCircuitOpen. The same CircuitBreakers should
+// be used for all Endpoints that access the same API.
+func WithCircuitBreakers(cb *CircuitBreakers) Option {
+	return func(o *options) {
+		o.circuitBreakers = cb
+	}
+}
+
+// WithResponseCache specifies a ResponseCache to use for making GET
+// requests conditional on the resource having changed since it was
+// last fetched. Responses that carry an ETag or Last-Modified header
+// are cached and subsequent requests for the same URL are sent with
+// If-None-Match and/or If-Modified-Since headers. A 304 Not Modified
+// response is then satisfied from the cache. Note that responses are not
+// cached when streaming is enabled via WithStreaming.
+func WithResponseCache(c ResponseCache) Option {
+	return func(o *options) {
+		o.responseCache = c
+	}
+}
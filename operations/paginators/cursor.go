@@ -0,0 +1,160 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package paginators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Cursor is an operations.Paginator for APIs that return an opaque token,
+// or cursor, with each page that is passed as a query parameter to obtain
+// the next page. The scan is complete when a page has no token or the same
+// token as the previous page.
+type Cursor[T any] struct {
+	Request
+	// Param is the name of the query parameter used for the token, it
+	// defaults to "cursor".
+	Param string
+	// Token returns the token for the next page.
+	Token Extractor[T, string]
+
+	cursor string
+	done   bool
+}
+
+// NewCursor returns a new Cursor paginator.
+func NewCursor[T any](req Request, param string, token Extractor[T, string]) *Cursor[T] {
+	return &Cursor[T]{Request: req, Param: param, Token: token}
+}
+
+// Next implements operations.Paginator.
+func (pg *Cursor[T]) Next(ctx context.Context, payload T, resp *http.Response) (*http.Request, bool, error) {
+	if pg.Token == nil {
+		return nil, true, fmt.Errorf("paginators.Cursor: Token must be set")
+	}
+	if resp != nil {
+		token, err := extract("token", pg.Token, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		pg.done = len(token) == 0 || token == pg.cursor
+		pg.cursor = token
+	}
+	if pg.done {
+		return nil, true, nil
+	}
+	req, err := pg.newRequest(ctx, func(params url.Values) {
+		if len(pg.cursor) > 0 {
+			params.Set(paramOrDefault(pg.Param, "cursor"), pg.cursor)
+		}
+	})
+	return req, false, err
+}
+
+type cursorState struct {
+	Cursor string `json:"cursor,omitempty"`
+	Done   bool   `json:"done"`
+}
+
+// MarshalState implements operations.ResumablePaginator.
+func (pg *Cursor[T]) MarshalState() ([]byte, error) {
+	return json.Marshal(cursorState{Cursor: pg.cursor, Done: pg.done})
+}
+
+// UnmarshalState implements operations.ResumablePaginator.
+func (pg *Cursor[T]) UnmarshalState(buf []byte) error {
+	var state cursorState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return err
+	}
+	pg.cursor, pg.done = state.Cursor, state.Done
+	return nil
+}
+
+// ScrollID is an operations.Paginator for APIs that return a scroll ID
+// with each page that is passed as a query parameter to obtain the next
+// page, but which rely on the client counting items to determine when
+// the scan is complete since the last scroll ID is typically repeated
+// rather than omitted. The scan is complete when a page has no items, no
+// scroll ID, or when the total number of items, if known, has been reached.
+type ScrollID[T any] struct {
+	Request
+	// Param is the name of the query parameter used for the scroll ID,
+	// it defaults to "scroll_id".
+	Param string
+	// ID returns the scroll ID for the next page.
+	ID Extractor[T, string]
+	// Count returns the number of items in a page.
+	Count Extractor[T, int]
+	// Total, if set, returns the total number of items available.
+	Total Extractor[T, int64]
+
+	id      string
+	fetched int64
+	done    bool
+}
+
+// NewScrollID returns a new ScrollID paginator.
+func NewScrollID[T any](req Request, param string, id Extractor[T, string], count Extractor[T, int], total Extractor[T, int64]) *ScrollID[T] {
+	return &ScrollID[T]{Request: req, Param: param, ID: id, Count: count, Total: total}
+}
+
+// Next implements operations.Paginator.
+func (pg *ScrollID[T]) Next(ctx context.Context, payload T, resp *http.Response) (*http.Request, bool, error) {
+	if pg.ID == nil || pg.Count == nil {
+		return nil, true, fmt.Errorf("paginators.ScrollID: both ID and Count must be set")
+	}
+	if resp != nil {
+		id, err := extract("scroll id", pg.ID, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		n, err := extract("count", pg.Count, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		total, err := extract("total", pg.Total, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		pg.fetched += int64(n)
+		pg.id = id
+		pg.done = n == 0 || len(id) == 0 || (pg.Total != nil && pg.fetched >= total)
+	}
+	if pg.done {
+		return nil, true, nil
+	}
+	req, err := pg.newRequest(ctx, func(params url.Values) {
+		if len(pg.id) > 0 {
+			params.Set(paramOrDefault(pg.Param, "scroll_id"), pg.id)
+		}
+	})
+	return req, false, err
+}
+
+type scrollIDState struct {
+	ID      string `json:"id,omitempty"`
+	Fetched int64  `json:"fetched"`
+	Done    bool   `json:"done"`
+}
+
+// MarshalState implements operations.ResumablePaginator.
+func (pg *ScrollID[T]) MarshalState() ([]byte, error) {
+	return json.Marshal(scrollIDState{ID: pg.id, Fetched: pg.fetched, Done: pg.done})
+}
+
+// UnmarshalState implements operations.ResumablePaginator.
+func (pg *ScrollID[T]) UnmarshalState(buf []byte) error {
+	var state scrollIDState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return err
+	}
+	pg.id, pg.fetched, pg.done = state.ID, state.Fetched, state.Done
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package paginators
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// LinkHeader is an operations.Paginator for APIs, such as GitHub's, that
+// use RFC 8288 Link headers with a rel="next" link to refer to the
+// next page. The scan is complete when a response has no such link.
+type LinkHeader[T any] struct {
+	Request
+	// Rel is the link relation type to follow, it defaults to "next".
+	Rel string
+
+	next string
+	done bool
+}
+
+// NewLinkHeader returns a new LinkHeader paginator. Only the URL and
+// Header fields of req are used, and URL must include any query
+// parameters required for the first page.
+func NewLinkHeader[T any](req Request) *LinkHeader[T] {
+	return &LinkHeader[T]{Request: req}
+}
+
+// Next implements operations.Paginator.
+func (pg *LinkHeader[T]) Next(ctx context.Context, _ T, resp *http.Response) (*http.Request, bool, error) {
+	if resp != nil {
+		pg.next = NextLink(resp, pg.rel())
+		pg.done = len(pg.next) == 0
+	}
+	if pg.done {
+		return nil, true, nil
+	}
+	u := pg.URL
+	if len(pg.next) > 0 {
+		u = pg.next
+	}
+	req, err := pg.newGetRequest(ctx, u)
+	return req, false, err
+}
+
+func (pg *LinkHeader[T]) rel() string {
+	if len(pg.Rel) == 0 {
+		return "next"
+	}
+	return pg.Rel
+}
+
+type linkHeaderState struct {
+	Next string `json:"next,omitempty"`
+	Done bool   `json:"done"`
+}
+
+// MarshalState implements operations.ResumablePaginator.
+func (pg *LinkHeader[T]) MarshalState() ([]byte, error) {
+	return json.Marshal(linkHeaderState{Next: pg.next, Done: pg.done})
+}
+
+// UnmarshalState implements operations.ResumablePaginator.
+func (pg *LinkHeader[T]) UnmarshalState(buf []byte) error {
+	var state linkHeaderState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return err
+	}
+	pg.next, pg.done = state.Next, state.Done
+	return nil
+}
+
+// NextLink returns the target of the first RFC 8288 Link header in resp
+// with the specified relation type, resolved relative to the request's
+// URL, or an empty string if there is no such link.
+func NextLink(resp *http.Response, rel string) string {
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range splitLinks(header) {
+			target, params, ok := parseLink(link)
+			if !ok || !hasRel(params, rel) {
+				continue
+			}
+			if resp.Request == nil || resp.Request.URL == nil {
+				return target
+			}
+			u, err := resp.Request.URL.Parse(target)
+			if err != nil {
+				continue
+			}
+			return u.String()
+		}
+	}
+	return ""
+}
+
+// splitLinks splits a Link header value into its comma separated
+// link-values, allowing for commas within the <> delimited targets
+// and quoted parameter values.
+func splitLinks(header string) []string {
+	var links []string
+	inURL, inQuote, start := false, false, 0
+	for i, c := range header {
+		switch {
+		case c == '<' && !inQuote:
+			inURL = true
+		case c == '>' && !inQuote:
+			inURL = false
+		case c == '"' && !inURL:
+			inQuote = !inQuote
+		case c == ',' && !inURL && !inQuote:
+			links = append(links, header[start:i])
+			start = i + 1
+		}
+	}
+	return append(links, header[start:])
+}
+
+func parseLink(link string) (string, map[string]string, bool) {
+	link = strings.TrimSpace(link)
+	if !strings.HasPrefix(link, "<") {
+		return "", nil, false
+	}
+	end := strings.IndexByte(link, '>')
+	if end < 0 {
+		return "", nil, false
+	}
+	target := link[1:end]
+	params := map[string]string{}
+	for _, param := range strings.Split(link[end+1:], ";") {
+		k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if len(k) == 0 {
+			continue
+		}
+		params[strings.ToLower(strings.TrimSpace(k))] = strings.Trim(strings.TrimSpace(v), `"`)
+	}
+	return target, params, true
+}
+
+func hasRel(params map[string]string, rel string) bool {
+	for _, r := range strings.Fields(params["rel"]) {
+		if strings.EqualFold(r, rel) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package paginators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// OffsetLimit is an operations.Paginator for APIs that accept the offset
+// of the first item to return and the maximum number of items to return.
+// The scan is complete when a page contains no items, when the total
+// number of items, if known, has been reached, or, if the total is not
+// known, when a page contains fewer than Limit items.
+type OffsetLimit[T any] struct {
+	Request
+	// OffsetParam and LimitParam are the names of the query parameters
+	// used for the offset and limit, they default to "offset" and "limit".
+	OffsetParam, LimitParam string
+	// Limit is the number of items to request per page. The limit
+	// parameter is omitted if Limit is zero.
+	Limit int
+	// Count returns the number of items in a page.
+	Count Extractor[T, int]
+	// Total, if set, returns the total number of items available.
+	Total Extractor[T, int64]
+
+	offset int64
+	done   bool
+}
+
+// NewOffsetLimit returns a new OffsetLimit paginator.
+func NewOffsetLimit[T any](req Request, limit int, count Extractor[T, int]) *OffsetLimit[T] {
+	return &OffsetLimit[T]{Request: req, Limit: limit, Count: count}
+}
+
+// Next implements operations.Paginator.
+func (pg *OffsetLimit[T]) Next(ctx context.Context, payload T, resp *http.Response) (*http.Request, bool, error) {
+	if pg.Count == nil {
+		return nil, true, fmt.Errorf("paginators.OffsetLimit: Count must be set")
+	}
+	if resp != nil {
+		n, err := extract("count", pg.Count, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		total, err := extract("total", pg.Total, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		pg.offset += int64(n)
+		switch {
+		case n == 0:
+			pg.done = true
+		case pg.Total != nil:
+			pg.done = pg.offset >= total
+		default:
+			pg.done = pg.Limit > 0 && n < pg.Limit
+		}
+	}
+	if pg.done {
+		return nil, true, nil
+	}
+	req, err := pg.newRequest(ctx, func(params url.Values) {
+		params.Set(paramOrDefault(pg.OffsetParam, "offset"), strconv.FormatInt(pg.offset, 10))
+		if pg.Limit > 0 {
+			params.Set(paramOrDefault(pg.LimitParam, "limit"), strconv.Itoa(pg.Limit))
+		}
+	})
+	return req, false, err
+}
+
+type offsetLimitState struct {
+	Offset int64 `json:"offset"`
+	Done   bool  `json:"done"`
+}
+
+// MarshalState implements operations.ResumablePaginator.
+func (pg *OffsetLimit[T]) MarshalState() ([]byte, error) {
+	return json.Marshal(offsetLimitState{Offset: pg.offset, Done: pg.done})
+}
+
+// UnmarshalState implements operations.ResumablePaginator.
+func (pg *OffsetLimit[T]) UnmarshalState(buf []byte) error {
+	var state offsetLimitState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return err
+	}
+	pg.offset, pg.done = state.Offset, state.Done
+	return nil
+}
+
+// PageNumber is an operations.Paginator for APIs that accept a page number
+// and, optionally, a page size. The scan is complete when the total number
+// of pages, if known, has been reached, or when a page contains no items,
+// or fewer than Size items. At least one of Pages or Count must be set.
+type PageNumber[T any] struct {
+	Request
+	// PageParam and SizeParam are the names of the query parameters used
+	// for the page number and page size, they default to "page" and
+	// "per_page".
+	PageParam, SizeParam string
+	// ZeroBased should be set if the first page is numbered 0 rather than 1.
+	ZeroBased bool
+	// Size is the number of items to request per page. The size parameter
+	// is omitted if Size is zero.
+	Size int
+	// Count, if set, returns the number of items in a page.
+	Count Extractor[T, int]
+	// Pages, if set, returns the total number of pages available.
+	Pages Extractor[T, int64]
+
+	completed int64
+	done      bool
+}
+
+// NewPageNumber returns a new PageNumber paginator.
+func NewPageNumber[T any](req Request, size int, count Extractor[T, int], pages Extractor[T, int64]) *PageNumber[T] {
+	return &PageNumber[T]{Request: req, Size: size, Count: count, Pages: pages}
+}
+
+// Next implements operations.Paginator.
+func (pg *PageNumber[T]) Next(ctx context.Context, payload T, resp *http.Response) (*http.Request, bool, error) {
+	if pg.Count == nil && pg.Pages == nil {
+		return nil, true, fmt.Errorf("paginators.PageNumber: at least one of Count or Pages must be set")
+	}
+	if resp != nil {
+		n, err := extract("count", pg.Count, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		pages, err := extract("pages", pg.Pages, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		pg.completed++
+		switch {
+		case pg.Pages != nil:
+			pg.done = pg.completed >= pages
+		default:
+			pg.done = n == 0 || (pg.Size > 0 && n < pg.Size)
+		}
+	}
+	if pg.done {
+		return nil, true, nil
+	}
+	page := pg.completed + 1
+	if pg.ZeroBased {
+		page = pg.completed
+	}
+	req, err := pg.newRequest(ctx, func(params url.Values) {
+		params.Set(paramOrDefault(pg.PageParam, "page"), strconv.FormatInt(page, 10))
+		if pg.Size > 0 {
+			params.Set(paramOrDefault(pg.SizeParam, "per_page"), strconv.Itoa(pg.Size))
+		}
+	})
+	return req, false, err
+}
+
+type pageNumberState struct {
+	Completed int64 `json:"completed"`
+	Done      bool  `json:"done"`
+}
+
+// MarshalState implements operations.ResumablePaginator.
+func (pg *PageNumber[T]) MarshalState() ([]byte, error) {
+	return json.Marshal(pageNumberState{Completed: pg.completed, Done: pg.done})
+}
+
+// UnmarshalState implements operations.ResumablePaginator.
+func (pg *PageNumber[T]) UnmarshalState(buf []byte) error {
+	var state pageNumberState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return err
+	}
+	pg.completed, pg.done = state.Completed, state.Done
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+// Package paginators provides configurable implementations of
+// operations.Paginator for the most commonly used pagination schemes:
+// RFC 8288 Link headers, offset/limit, page numbers, opaque cursor
+// tokens and scroll IDs. All of the paginators implement
+// operations.ResumablePaginator.
+//
+// The values required to determine the next page, such as a cursor token
+// or the total number of items, are obtained from each page's payload
+// using an Extractor, which may be created from an accessor function
+// using Func or from a JSON path using JSONPath.
+package paginators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Extractor extracts a value, such as the next cursor token or the total
+// number of items, from a page's payload.
+type Extractor[T, V any] func(payload T) (V, error)
+
+// Func returns an Extractor that calls the supplied accessor function.
+func Func[T, V any](fn func(T) V) Extractor[T, V] {
+	return func(payload T) (V, error) {
+		return fn(payload), nil
+	}
+}
+
+// JSONPath returns an Extractor that obtains the value at the specified
+// path within the JSON encoding of a page's payload. The path consists
+// of dot separated object keys and array indices, for example
+// "meta.pagination.next" or "items.0.id". A missing or null value results
+// in the zero value for V being returned rather than an error since
+// APIs typically omit the next token on the last page.
+func JSONPath[T, V any](path string) Extractor[T, V] {
+	var keys []string
+	if len(path) > 0 {
+		keys = strings.Split(path, ".")
+	}
+	return func(payload T) (V, error) {
+		var v V
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return v, err
+		}
+		var node any
+		if err := json.Unmarshal(buf, &node); err != nil {
+			return v, err
+		}
+		for _, key := range keys {
+			switch n := node.(type) {
+			case map[string]any:
+				node = n[key]
+			case []any:
+				idx, err := strconv.Atoi(key)
+				if err != nil || idx < 0 || idx >= len(n) {
+					return v, nil
+				}
+				node = n[idx]
+			default:
+				return v, nil
+			}
+			if node == nil {
+				return v, nil
+			}
+		}
+		buf, err = json.Marshal(node)
+		if err != nil {
+			return v, err
+		}
+		if err := json.Unmarshal(buf, &v); err != nil {
+			return v, fmt.Errorf("%v: %w", path, err)
+		}
+		return v, nil
+	}
+}
+
+// Request specifies how the request for each page is created.
+type Request struct {
+	// URL is the URL of the first page, and for all but the LinkHeader
+	// paginator, of subsequent pages with the pagination parameters
+	// added to its query parameters.
+	URL string
+	// Parameters are query parameters to be included in every request.
+	Parameters url.Values
+	// Header contains headers to be included in every request.
+	Header http.Header
+	// NewRequest, if set, is used to create the request for each page
+	// given the query parameters for that page, including the pagination
+	// parameters, and overrides URL and Header. It allows for APIs
+	// that expect pagination parameters to be encoded in the URL's
+	// path or in a request body.
+	NewRequest func(ctx context.Context, params url.Values) (*http.Request, error)
+}
+
+func (r Request) newRequest(ctx context.Context, set func(url.Values)) (*http.Request, error) {
+	params := url.Values{}
+	for k, v := range r.Parameters {
+		params[k] = append([]string(nil), v...)
+	}
+	set(params)
+	if r.NewRequest != nil {
+		return r.NewRequest(ctx, params)
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return r.newGetRequest(ctx, u.String())
+}
+
+func (r Request) newGetRequest(ctx context.Context, u string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	return req, nil
+}
+
+func extract[T, V any](name string, fn Extractor[T, V], payload T) (V, error) {
+	if fn == nil {
+		var v V
+		return v, nil
+	}
+	v, err := fn(payload)
+	if err != nil {
+		return v, fmt.Errorf("failed to extract %v: %w", name, err)
+	}
+	return v, nil
+}
+
+func paramOrDefault(param, def string) string {
+	if len(param) == 0 {
+		return def
+	}
+	return param
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package paginators_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/operations/paginators"
+)
+
+type page struct {
+	Items []int `json:"items"`
+	Meta  struct {
+		Total int64  `json:"total"`
+		Pages int64  `json:"pages"`
+		Next  string `json:"next,omitempty"`
+	} `json:"meta"`
+}
+
+const numItems = 10
+
+// newServer returns a server that serves numItems items, three per page,
+// using the pagination scheme determined by the request's query parameters.
+func newServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		start := 0
+		intParam := func(name string) int {
+			v, err := strconv.Atoi(q.Get(name))
+			if err != nil {
+				t.Errorf("%v: %v", name, err)
+			}
+			return v
+		}
+		switch {
+		case q.Has("offset"):
+			start = intParam("offset")
+		case q.Has("page"):
+			start = (intParam("page") - 1) * 3
+		case q.Has("cursor"):
+			start = intParam("cursor")
+		case q.Has("scroll_id"):
+			start = intParam("scroll_id")
+		}
+		var p page
+		for i := start; i < start+3 && i < numItems; i++ {
+			p.Items = append(p.Items, i)
+		}
+		p.Meta.Total = numItems
+		p.Meta.Pages = (numItems + 2) / 3
+		if next := start + 3; next < numItems {
+			p.Meta.Next = strconv.Itoa(next)
+			if q.Has("link") {
+				w.Header().Add("Link", fmt.Sprintf(`</items?link&cursor=%v>; rel="next", </items?link&cursor=0>; rel="first"`, next))
+			}
+		}
+		if q.Has("scroll_id") {
+			// Scroll IDs are returned for every page, including any
+			// past the end of the list.
+			p.Meta.Next = strconv.Itoa(start + 3)
+		}
+		_ = json.NewEncoder(w).Encode(p)
+	}))
+}
+
+func scan(ctx context.Context, t *testing.T, pg operations.Paginator[page]) []int {
+	t.Helper()
+	var items []int
+	sc := operations.NewScanner(pg)
+	for sc.Scan(ctx) {
+		items = append(items, sc.Response().Items...)
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func count(p page) int { return len(p.Items) }
+
+func TestPaginators(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	defer srv.Close()
+
+	var all []int
+	for i := range numItems {
+		all = append(all, i)
+	}
+
+	req := paginators.Request{URL: srv.URL + "/items"}
+	total := paginators.JSONPath[page, int64]("meta.total")
+	next := paginators.JSONPath[page, string]("meta.next")
+
+	offset := paginators.NewOffsetLimit(req, 3, paginators.Func(count))
+	pageNumber := paginators.NewPageNumber(req, 3, nil, paginators.JSONPath[page, int64]("meta.pages"))
+	pageCount := paginators.NewPageNumber(req, 3, paginators.Func(count), nil)
+	offsetTotal := paginators.NewOffsetLimit(req, 0, paginators.Func(count))
+	offsetTotal.Total = total
+	for i, pg := range []operations.Paginator[page]{
+		paginators.NewLinkHeader[page](paginators.Request{URL: srv.URL + "/items?link"}),
+		offset,
+		offsetTotal,
+		pageNumber,
+		pageCount,
+		paginators.NewCursor(req, "cursor", next),
+		paginators.NewScrollID(req, "scroll_id", next, paginators.Func(count), total),
+		paginators.NewScrollID(req, "scroll_id", next, paginators.Func(count), nil),
+	} {
+		if got, want := scan(ctx, t, pg), all; !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: %T: got %v, want %v", i, pg, got, want)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	defer srv.Close()
+
+	req := paginators.Request{
+		URL:        srv.URL + "/items",
+		Parameters: url.Values{"size": []string{"3"}},
+	}
+	newPaginators := func() []operations.Paginator[page] {
+		return []operations.Paginator[page]{
+			paginators.NewLinkHeader[page](paginators.Request{URL: srv.URL + "/items?link"}),
+			paginators.NewOffsetLimit(req, 3, paginators.Func(count)),
+			paginators.NewPageNumber(req, 3, paginators.Func(count), nil),
+			paginators.NewCursor(req, "", paginators.JSONPath[page, string]("meta.next")),
+			paginators.NewScrollID(req, "", paginators.JSONPath[page, string]("meta.next"), paginators.Func(count), nil),
+		}
+	}
+	for i, pg := range newPaginators() {
+		sc := operations.NewScanner(pg)
+		if !sc.Scan(ctx) || !sc.Scan(ctx) {
+			t.Fatalf("%v: %T: scan failed: %v", i, pg, sc.Err())
+		}
+		state := sc.Checkpoint()
+		sc, err := operations.NewScannerFrom(newPaginators()[i], state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var items []int
+		for sc.Scan(ctx) {
+			items = append(items, sc.Response().Items...)
+		}
+		if err := sc.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := items, []int{6, 7, 8, 9}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: %T: got %v, want %v", i, pg, got, want)
+		}
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	type nested struct {
+		A struct {
+			B []struct {
+				C *string `json:"c"`
+			} `json:"b"`
+		} `json:"a"`
+	}
+	var n nested
+	v := "value"
+	n.A.B = append(n.A.B, struct {
+		C *string `json:"c"`
+	}{C: &v})
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{"a.b.0.c", "value"},
+		{"a.b.1.c", ""},
+		{"a.x", ""},
+		{"a.b.0.c.d", ""},
+	} {
+		got, err := paginators.JSONPath[nested, string](tc.path)(n)
+		if err != nil {
+			t.Errorf("%v: %v", tc.path, err)
+		}
+		if got != tc.want {
+			t.Errorf("%v: got %v, want %v", tc.path, got, tc.want)
+		}
+	}
+	if _, err := paginators.JSONPath[nested, int]("a.b.0.c")(n); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	u, _ := url.Parse("https://example.com/api/items?page=1")
+	resp := &http.Response{
+		Header:  http.Header{},
+		Request: &http.Request{URL: u},
+	}
+	resp.Header.Add("Link", `<https://example.com/api/items?page=1&a=b,c>; rel="prev first"`)
+	resp.Header.Add("Link", `<items?page=2>; title="a, b"; rel=next`)
+	if got, want := paginators.NextLink(resp, "next"), "https://example.com/api/items?page=2"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := paginators.NextLink(resp, "first"), "https://example.com/api/items?page=1&a=b,c"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := paginators.NextLink(resp, "last"), ""; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMisconfigured(t *testing.T) {
+	ctx := context.Background()
+	req := paginators.Request{URL: "http://example.com/items"}
+	for i, pg := range []operations.Paginator[page]{
+		paginators.NewOffsetLimit[page](req, 3, nil),
+		paginators.NewPageNumber[page](req, 3, nil, nil),
+		paginators.NewCursor[page](req, "cursor", nil),
+		paginators.NewScrollID(req, "scroll_id", nil, paginators.Func(count), nil),
+		paginators.NewScrollID(req, "scroll_id", paginators.JSONPath[page, string]("meta.next"), nil, nil),
+	} {
+		if _, done, err := pg.Next(ctx, page{}, nil); err == nil || !done {
+			t.Errorf("%v: %T: expected an error", i, pg)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"net/http"
+)
+
+// PrefetchPaginator is an optional extension to Paginator that may be
+// implemented by paginators for APIs where the requests for all subsequent
+// pages can be determined from the response to the first request, eg.
+// APIs that use page numbers or offsets and that return the total number
+// of pages or items. A Scanner created with WithPrefetch will use such a
+// paginator to fetch several pages concurrently whilst still delivering
+// them in order. Note that Next is still called for every page, in order,
+// to allow the paginator to update its state and to end the scan early.
+type PrefetchPaginator[T any] interface {
+	Paginator[T]
+	// Upcoming is called once with the response to the first request and
+	// returns the requests for all subsequent pages in the order in which
+	// they are to be delivered.
+	Upcoming(ctx context.Context, t T, r *http.Response) ([]*http.Request, error)
+}
+
+// WithPrefetch specifies the number of pages that a Scanner may fetch
+// concurrently, and hence ahead of the page currently being processed,
+// when its paginator implements PrefetchPaginator. The default of 1
+// fetches a single page ahead, as do paginators that do not implement
+// PrefetchPaginator. All requests are subject to the Endpoint's rate
+// controller.
+func WithPrefetch(concurrency int) Option {
+	return func(o *options) {
+		o.prefetch = concurrency
+	}
+}
+
+// prefetcher fetches a known set of requests concurrently, using at most
+// concurrency goroutines, and delivers the results in order. The number
+// of results fetched but not yet delivered is also limited by concurrency.
+type prefetcher[T any] struct {
+	slots  []chan response[T]
+	sem    chan struct{}
+	next   int
+	cancel context.CancelFunc
+}
+
+func newPrefetcher[T any](ctx context.Context, ep *Endpoint[T], reqs []*http.Request, concurrency int) *prefetcher[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	pf := &prefetcher[T]{
+		slots:  make([]chan response[T], len(reqs)),
+		sem:    make(chan struct{}, concurrency),
+		cancel: cancel,
+	}
+	for i := range pf.slots {
+		pf.slots[i] = make(chan response[T], 1)
+	}
+	go func() {
+		for i, req := range reqs {
+			select {
+			case pf.sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(ch chan<- response[T], req *http.Request) {
+				ctx, span := StartSpan(ctx, SpanScanPage, Attr("webapi.prefetched", true))
+				res, err := ep.getWithResp(ctx, req.WithContext(ctx))
+				endSpan(span, err)
+				ch <- response[T]{
+					response:     res.value,
+					httpResponse: res.resp,
+					body:         res.body,
+					encoding:     res.encoding,
+					err:          err,
+				}
+			}(pf.slots[i], req)
+		}
+	}()
+	return pf
+}
+
+// get returns the next response in order, it returns false when all
+// responses have been delivered.
+func (pf *prefetcher[T]) get(ctx context.Context) (response[T], bool) {
+	if pf.next >= len(pf.slots) {
+		return response[T]{}, false
+	}
+	select {
+	case resp := <-pf.slots[pf.next]:
+		pf.next++
+		<-pf.sem
+		return resp, true
+	case <-ctx.Done():
+		return response[T]{err: ctx.Err()}, true
+	}
+}
+
+func (pf *prefetcher[T]) remaining() int {
+	return len(pf.slots) - pf.next
+}
+
+func (pf *prefetcher[T]) stop() {
+	pf.cancel()
+}
+
+// startPrefetch starts prefetching pages if the paginator supports it
+// and prefetching is enabled, it returns true if prefetching was started.
+func (sc *Scanner[T]) startPrefetch(ctx context.Context, resp response[T]) (bool, error) {
+	if sc.prefetchChecked || sc.ep.prefetch <= 1 {
+		return false, nil
+	}
+	sc.prefetchChecked = true
+	pp, ok := sc.paginator.(PrefetchPaginator[T])
+	if !ok {
+		return false, nil
+	}
+	reqs, err := pp.Upcoming(ctx, resp.response, resp.httpResponse)
+	if err != nil || len(reqs) == 0 {
+		return false, err
+	}
+	sc.prefetcher = newPrefetcher(ctx, sc.ep, reqs, sc.ep.prefetch)
+	return true, nil
+}
+
+// scanPrefetched returns the next prefetched page, calling the paginator's
+// Next method for it so that the paginator can track its state and end the
+// scan early.
+func (sc *Scanner[T]) scanPrefetched(ctx context.Context) bool {
+	resp, ok := sc.prefetcher.get(ctx)
+	if !ok {
+		sc.done = true
+		sc.prefetcher.stop()
+		return false
+	}
+	if resp.err != nil {
+		sc.err = resp.err
+		sc.prefetcher.stop()
+		return false
+	}
+	_, last, err := sc.paginator.Next(ctx, resp.response, resp.httpResponse)
+	if err == nil {
+		resp.state, err = sc.marshalState()
+	}
+	if err != nil {
+		sc.err = err
+		sc.prefetcher.stop()
+		return false
+	}
+	sc.resp = resp
+	sc.recordPage()
+	if last || sc.prefetcher.remaining() == 0 {
+		sc.done = true
+		sc.prefetcher.stop()
+	}
+	return true
+}
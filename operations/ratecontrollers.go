@@ -0,0 +1,114 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"cloudeng.io/net/ratecontrol"
+)
+
+// RateControllers is a registry of ratecontrol.Controllers keyed by host
+// and API key ID. It allows for all of the Endpoints, Scanners and Fetchers
+// that access the same service with the same credentials to share a
+// single rate limit budget, see WithSharedRateControl.
+type RateControllers struct {
+	mu          sync.Mutex
+	controllers map[rateControllerKey]*ratecontrol.Controller
+}
+
+type rateControllerKey struct {
+	host, keyID string
+}
+
+// NewRateControllers returns a new, empty, RateControllers.
+func NewRateControllers() *RateControllers {
+	return &RateControllers{controllers: map[rateControllerKey]*ratecontrol.Controller{}}
+}
+
+// DefaultRateControllers is the process-wide RateControllers used by
+// WithSharedRateControl when no registry is specified.
+var DefaultRateControllers = NewRateControllers()
+
+func newRateControllerKey(host, keyID string) rateControllerKey {
+	return rateControllerKey{host: strings.ToLower(host), keyID: keyID}
+}
+
+// Get returns the controller for the specified host and key ID, creating
+// it by calling newController if there is no such controller. If
+// newController is nil, a controller created by ratecontrol.New with no
+// options, ie. with no rate limit, is used. The host may include a port.
+func (r *RateControllers) Get(host, keyID string, newController func() *ratecontrol.Controller) *ratecontrol.Controller {
+	key := newRateControllerKey(host, keyID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rc, ok := r.controllers[key]; ok {
+		return rc
+	}
+	var rc *ratecontrol.Controller
+	if newController != nil {
+		rc = newController()
+	}
+	if rc == nil {
+		rc = ratecontrol.New()
+	}
+	r.controllers[key] = rc
+	return rc
+}
+
+// Set sets the controller for the specified host and key ID, replacing
+// any existing controller. It may be used to configure the rate limit
+// for a service in advance of any requests being made to it.
+func (r *RateControllers) Set(host, keyID string, rc *ratecontrol.Controller) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.controllers[newRateControllerKey(host, keyID)] = rc
+}
+
+// Delete removes the controller for the specified host and key ID.
+func (r *RateControllers) Delete(host, keyID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.controllers, newRateControllerKey(host, keyID))
+}
+
+type sharedRateControl struct {
+	registry      *RateControllers
+	keyID         string
+	newController func() *ratecontrol.Controller
+}
+
+// WithSharedRateControl specifies that the rate controller used for each
+// request is to be obtained from the supplied registry, or
+// DefaultRateControllers if registry is nil, using the request's host and
+// the specified API key ID. The newController function, if non-nil, is
+// used to create the controller for a host and key ID that is not already
+// in the registry. The status codes are those that trigger backoff as per
+// WithRateController. WithSharedRateControl and WithRateController are
+// mutually exclusive, the last one specified takes effect.
+func WithSharedRateControl(registry *RateControllers, keyID string, newController func() *ratecontrol.Controller, statusCodes ...int) Option {
+	return func(o *options) {
+		if registry == nil {
+			registry = DefaultRateControllers
+		}
+		o.backoffStatusCodes = statusCodes
+		o.rateController = nil
+		o.sharedRateControl = &sharedRateControl{
+			registry:      registry,
+			keyID:         keyID,
+			newController: newController,
+		}
+	}
+}
+
+// rateControllerFor returns the rate controller to use for req.
+func (ep *Endpoint[T]) rateControllerFor(req *http.Request) *ratecontrol.Controller {
+	if src := ep.sharedRateControl; src != nil {
+		return src.registry.Get(req.URL.Host, src.keyID, src.newController)
+	}
+	return ep.rateController
+}
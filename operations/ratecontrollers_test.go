@@ -0,0 +1,87 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func TestRateControllers(t *testing.T) {
+	registry := operations.NewRateControllers()
+	created := 0
+	newController := func() *ratecontrol.Controller {
+		created++
+		return ratecontrol.New()
+	}
+	a := registry.Get("example.com", "key", newController)
+	if got, want := registry.Get("EXAMPLE.com", "key", newController), a; got != want {
+		t.Errorf("got %p, want %p", got, want)
+	}
+	if got := registry.Get("example.com", "other", newController); got == a {
+		t.Errorf("key IDs should not share controllers")
+	}
+	if got := registry.Get("example.com:8080", "key", nil); got == a || got == nil {
+		t.Errorf("hosts should not share controllers")
+	}
+	if got, want := created, 2; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	b := ratecontrol.New()
+	registry.Set("example.com", "key", b)
+	if got, want := registry.Get("example.com", "key", newController), b; got != want {
+		t.Errorf("got %p, want %p", got, want)
+	}
+	registry.Delete("example.com", "key")
+	if got := registry.Get("example.com", "key", newController); got == b {
+		t.Errorf("controller was not deleted")
+	}
+}
+
+func TestSharedRateControl(t *testing.T) {
+	ctx := context.Background()
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	tick := 50 * time.Millisecond
+	registry := operations.NewRateControllers()
+	registry.Set(u.Host, "key", ratecontrol.New(ratecontrol.WithRequestsPerTick(tick, 1)))
+
+	// Endpoints created for each call share the same rate limit.
+	start := time.Now()
+	for range 3 {
+		ep := operations.NewEndpoint[example](operations.WithSharedRateControl(registry, "key", nil))
+		if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := time.Since(start), 2*tick; got < want {
+		t.Errorf("got %v, want >= %v", got, want)
+	}
+
+	// WithRateController overrides a previous WithSharedRateControl.
+	start = time.Now()
+	for range 3 {
+		ep := operations.NewEndpoint[example](
+			operations.WithSharedRateControl(registry, "key", nil),
+			operations.WithRateController(ratecontrol.New()))
+		if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := time.Since(start), tick; got >= want {
+		t.Errorf("got %v, want < %v", got, want)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudeng.io/logging/ctxlog"
+)
+
+// RateLimitHeaders specifies the response headers used by an API to
+// indicate how long a client should wait before retrying a request that
+// was rejected due to rate limiting. Header names are tried in order and
+// the first one present in a response is used.
+type RateLimitHeaders struct {
+	// RetryAfter headers contain either a delay in seconds or an
+	// HTTP-date, as per the standard Retry-After header.
+	RetryAfter []string
+	// Remaining headers contain the number of requests remaining in the
+	// current quota window.
+	Remaining []string
+	// Reset headers contain the time at which the current quota window
+	// is reset, either as a delay in seconds or, for values too large to
+	// be a plausible delay, as a unix epoch time in seconds.
+	Reset []string
+	// Limit headers contain the total number of requests allowed in
+	// each quota window.
+	Limit []string
+	// MaxDelay, if non-zero, caps the delay obtained from any header.
+	MaxDelay time.Duration
+}
+
+// DefaultRateLimitHeaders returns the RateLimitHeaders used by default,
+// namely Retry-After, the X-RateLimit-Remaining/Reset/Limit family and the
+// IETF draft RateLimit-Remaining/Reset/Limit headers with a MaxDelay of
+// 15 minutes.
+func DefaultRateLimitHeaders() RateLimitHeaders {
+	return RateLimitHeaders{
+		RetryAfter: []string{"Retry-After"},
+		Remaining:  []string{"X-RateLimit-Remaining", "X-Rate-Limit-Remaining", "RateLimit-Remaining"},
+		Reset:      []string{"X-RateLimit-Reset", "X-Rate-Limit-Reset", "RateLimit-Reset"},
+		Limit:      []string{"X-RateLimit-Limit", "X-Rate-Limit-Limit", "RateLimit-Limit"},
+		MaxDelay:   15 * time.Minute,
+	}
+}
+
+// epochThreshold is used to distinguish reset values that are unix
+// epoch times from those that are delays, no API specifies a delay of
+// more than a year.
+const epochThreshold = 365 * 24 * 60 * 60
+
+// Delay returns the delay specified by the response's headers, if any.
+// A Retry-After header takes precedence over a Reset header, which is
+// only used if the corresponding Remaining header is absent or indicates
+// that the quota is exhausted.
+func (h RateLimitHeaders) Delay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil || resp.Header == nil {
+		return 0, false
+	}
+	if v, ok := firstHeader(resp.Header, h.RetryAfter); ok {
+		if d, ok := parseRetryAfter(v, now); ok {
+			return h.capDelay(d), true
+		}
+	}
+	v, ok := firstHeader(resp.Header, h.Reset)
+	if !ok {
+		return 0, false
+	}
+	if rem, ok := firstHeader(resp.Header, h.Remaining); ok {
+		if n, err := strconv.ParseFloat(rem, 64); err == nil && n > 0 {
+			return 0, false
+		}
+	}
+	if d, ok := parseReset(v, now); ok {
+		return h.capDelay(d), true
+	}
+	return 0, false
+}
+
+// Quota represents the state of an API's rate limit quota as reported
+// by the headers of a response. Negative values indicate that the
+// corresponding header is not present or could not be parsed.
+type Quota struct {
+	// Remaining is the number of requests remaining in the current window.
+	Remaining float64
+	// Limit is the total number of requests allowed in each window.
+	Limit float64
+	// Reset is the time remaining until the current window is reset.
+	Reset time.Duration
+}
+
+// Quota returns the quota reported by the response's headers.
+func (h RateLimitHeaders) Quota(resp *http.Response, now time.Time) Quota {
+	q := Quota{Remaining: -1, Limit: -1, Reset: -1}
+	if resp == nil || resp.Header == nil {
+		return q
+	}
+	parse := func(names []string) float64 {
+		if v, ok := firstHeader(resp.Header, names); ok {
+			if n, err := strconv.ParseFloat(v, 64); err == nil && n >= 0 {
+				return n
+			}
+		}
+		return -1
+	}
+	q.Remaining = parse(h.Remaining)
+	q.Limit = parse(h.Limit)
+	if v, ok := firstHeader(resp.Header, h.Reset); ok {
+		if d, ok := parseReset(v, now); ok {
+			q.Reset = h.capDelay(d)
+		}
+	}
+	return q
+}
+
+func (h RateLimitHeaders) capDelay(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	if h.MaxDelay > 0 && d > h.MaxDelay {
+		return h.MaxDelay
+	}
+	return d
+}
+
+func firstHeader(hdr http.Header, names []string) (string, bool) {
+	for _, n := range names {
+		if v := strings.TrimSpace(hdr.Get(n)); len(v) > 0 {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(now), true
+	}
+	return 0, false
+}
+
+func parseReset(v string, now time.Time) (time.Duration, bool) {
+	secs, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false
+	}
+	if secs > epochThreshold {
+		return time.Unix(int64(secs), 0).Sub(now), true
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
+
+// waitForServer waits for the remainder, if any, of the delay specified by
+// the rate limit headers in resp that has not already elapsed since the
+// specified time.
+func (ep *Endpoint[T]) waitForServer(ctx context.Context, req *http.Request, resp *http.Response, since time.Time) error {
+	delay, ok := ep.rateLimitHeaders.Delay(resp, since)
+	if !ok || delay == 0 {
+		return nil
+	}
+	remaining := delay - time.Since(since)
+	if remaining <= 0 {
+		return nil
+	}
+	ctxlog.Info(ctx, "server specified backoff", slog.Group("req", "url", ep.redactedURL(req.URL), "status", resp.StatusCode, "delay", delay, "remaining", remaining))
+	_, span := StartSpan(ctx, SpanBackoff, Attr("webapi.backoff.server_delay", delay.String()))
+	defer span.End()
+	select {
+	case <-ctx.Done():
+		span.RecordError(ctx.Err())
+		return ctx.Err()
+	case <-time.After(remaining):
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func TestRateLimitHeaders(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := operations.DefaultRateLimitHeaders()
+	for i, tc := range []struct {
+		headers map[string]string
+		delay   time.Duration
+		ok      bool
+	}{
+		{map[string]string{"Retry-After": "120"}, 2 * time.Minute, true},
+		{map[string]string{"Retry-After": now.Add(time.Minute).Format(http.TimeFormat)}, time.Minute, true},
+		{map[string]string{"Retry-After": "36000"}, 15 * time.Minute, true},
+		{map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"}, 30 * time.Second, true},
+		{map[string]string{"X-RateLimit-Reset": strconv.FormatInt(now.Add(10*time.Second).Unix(), 10)}, 10 * time.Second, true},
+		{map[string]string{"RateLimit-Remaining": "10", "RateLimit-Reset": "30"}, 0, false},
+		{map[string]string{"Retry-After": "5", "X-RateLimit-Reset": "30"}, 5 * time.Second, true},
+		{map[string]string{"Retry-After": "soon"}, 0, false},
+		{map[string]string{}, 0, false},
+	} {
+		resp := &http.Response{Header: http.Header{}}
+		for k, v := range tc.headers {
+			resp.Header.Set(k, v)
+		}
+		delay, ok := h.Delay(resp, now)
+		if got, want := delay, tc.delay; got != want {
+			t.Errorf("%v: got %v, want %v", i, got, want)
+		}
+		if got, want := ok, tc.ok; got != want {
+			t.Errorf("%v: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	ctx := context.Background()
+	count := 0
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		count++
+		if count == 1 {
+			w.Header().Set("X-Vendor-Wait", "10")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(count)
+	}))
+	defer srv.Close()
+
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 2))
+	client := operations.NewEndpoint[int](
+		operations.WithRateController(rc, http.StatusTooManyRequests),
+		operations.WithRateLimitHeaders(operations.RateLimitHeaders{
+			RetryAfter: []string{"X-Vendor-Wait"},
+			MaxDelay:   50 * time.Millisecond,
+		}))
+	start := time.Now()
+	n, _, _, err := client.Get(ctx, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := n, 2; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if took := time.Since(start); took < 50*time.Millisecond || took > 5*time.Second {
+		t.Errorf("server specified delay not honoured, or not capped: %v", took)
+	}
+}
+
+func TestRetryAfterBudget(t *testing.T) {
+	ctx := context.Background()
+	count := 0
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		count++
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(100*time.Millisecond, 2))
+	client := operations.NewEndpoint[int](
+		operations.WithRateController(rc, http.StatusServiceUnavailable),
+		operations.WithRateLimitHeaders(operations.RateLimitHeaders{
+			RetryAfter: []string{"Retry-After"},
+			MaxDelay:   200 * time.Millisecond,
+		}))
+	start := time.Now()
+	_, _, _, err := client.Get(ctx, srv.URL)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// Each retry counts against the backoff budget and the server's delay
+	// is used in place of, rather than in addition to, the backoff's delay.
+	if got, want := count, 3; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if took := time.Since(start); took < 400*time.Millisecond || took > 700*time.Millisecond {
+		t.Errorf("server specified delay was not used in place of the backoff delay: %v", took)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+)
+
+// Post invokes a POST request on this endpoint with the supplied body
+// encoded using the endpoint's marshaling function (json.Marshal by
+// default). Note that POST requests are only retried following a network
+// error if an idempotency key is attached to them, see WithIdempotencyKey.
+func (ep *Endpoint[T]) Post(ctx context.Context, url string, body any) (T, []byte, Encoding, error) {
+	return ep.withBody(ctx, http.MethodPost, url, body)
+}
+
+// Put invokes a PUT request on this endpoint with the supplied body
+// encoded using the endpoint's marshaling function.
+func (ep *Endpoint[T]) Put(ctx context.Context, url string, body any) (T, []byte, Encoding, error) {
+	return ep.withBody(ctx, http.MethodPut, url, body)
+}
+
+// Patch invokes a PATCH request on this endpoint with the supplied body
+// encoded using the endpoint's marshaling function. Note that PATCH
+// requests are only retried following a network error if an idempotency
+// key is attached to them, see WithIdempotencyKey.
+func (ep *Endpoint[T]) Patch(ctx context.Context, url string, body any) (T, []byte, Encoding, error) {
+	return ep.withBody(ctx, http.MethodPatch, url, body)
+}
+
+// Delete invokes a DELETE request on this endpoint, the body is optional
+// and is only sent if it is non-nil.
+func (ep *Endpoint[T]) Delete(ctx context.Context, url string, body any) (T, []byte, Encoding, error) {
+	return ep.withBody(ctx, http.MethodDelete, url, body)
+}
+
+func (ep *Endpoint[T]) withBody(ctx context.Context, method, url string, body any) (T, []byte, Encoding, error) {
+	req, err := ep.NewRequest(ctx, method, url, body)
+	if err != nil {
+		var result T
+		return result, nil, ep.encoding, err
+	}
+	return ep.get(ctx, req)
+}
+
+// NewRequest creates a new http.Request for the specified method and url
+// with the supplied body, if non-nil, encoded using the endpoint's
+// marshaling function. The request's GetBody field is set so that
+// the body can be re-sent when the request is retried.
+func (ep *Endpoint[T]) NewRequest(ctx context.Context, method, url string, body any) (*http.Request, error) {
+	if body == nil {
+		return http.NewRequestWithContext(ctx, method, url, nil)
+	}
+	buf, err := ep.marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", ep.contentType)
+	return req, nil
+}
+
+// isIdempotent returns true if the request can be safely retried
+// following a network error, ie. when it is not known if the server
+// received and acted on the request. This is the case for requests
+// that use an idempotent method or that carry an idempotency key.
+func (ep *Endpoint[T]) isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if len(ep.idempotencyHeader) > 0 && len(req.Header.Get(ep.idempotencyHeader)) > 0 {
+		return true
+	}
+	// Follow net/http's convention for recognising idempotency keys.
+	return len(req.Header.Get("Idempotency-Key")) > 0 || len(req.Header.Get("X-Idempotency-Key")) > 0
+}
+
+// setIdempotencyKey adds an idempotency key to requests that use a
+// non-idempotent method if so configured.
+func (ep *Endpoint[T]) setIdempotencyKey(req *http.Request) error {
+	if len(ep.idempotencyHeader) == 0 || ep.isIdempotent(req) {
+		return nil
+	}
+	if ep.idempotencyKey != nil {
+		req.Header.Set(ep.idempotencyHeader, ep.idempotencyKey())
+		return nil
+	}
+	var key [16]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return fmt.Errorf("failed to generate idempotency key: %w", err)
+	}
+	req.Header.Set(ep.idempotencyHeader, hex.EncodeToString(key[:]))
+	return nil
+}
+
+// rewindBody resets the body of a request that is to be retried.
+func (ep *Endpoint[T]) rewindBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody == nil {
+		return fmt.Errorf("cannot retry %v %v: request body cannot be re-read, GetBody is not set", req.Method, ep.redactedURL(req.URL))
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("cannot retry %v %v: %w", req.Method, ep.redactedURL(req.URL), err)
+	}
+	req.Body = body
+	return nil
+}
@@ -6,6 +6,7 @@ package operations
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 )
 
@@ -19,24 +20,43 @@ type Paginator[T any] interface {
 	Next(ctx context.Context, t T, r *http.Response) (req *http.Request, done bool, err error)
 }
 
+// ResumablePaginator is an optional interface that may be implemented
+// by a Paginator to allow a scan to be suspended and later resumed from
+// the same position, see Scanner.Checkpoint and NewScannerFrom.
+type ResumablePaginator interface {
+	// MarshalState returns an encoding of the paginator's current
+	// position, ie. following the most recent call to Next.
+	MarshalState() ([]byte, error)
+	// UnmarshalState restores the position encoded by MarshalState
+	// such that the next call to Next with a nil *http.Response
+	// returns the request for the page following that position, or
+	// done if there are no more pages.
+	UnmarshalState([]byte) error
+}
+
 type response[T any] struct {
 	response     T
 	httpResponse *http.Response
 	body         []byte
+	encoding     Encoding
 	last         bool
 	nextReq      *http.Request
+	state        []byte
 	err          error
 }
 
 // Scanner provides the ability to iterate over a paginated API a page at a time.
 type Scanner[T any] struct {
-	err       error
-	done      bool
-	paginator Paginator[T]
-	ep        *Endpoint[T]
-	ch        chan response[T]
-	resp      response[T]
-	opts      []Option
+	err        error
+	done       bool
+	paginator  Paginator[T]
+	ep         *Endpoint[T]
+	ch         chan response[T]
+	resp       response[T]
+	opts       []Option
+	prefetcher *prefetcher[T]
+
+	prefetchChecked bool
 }
 
 // NewScanner creates a new Scanner using the supplied paginator. The
@@ -49,6 +69,20 @@ func NewScanner[T any](paginator Paginator[T], opts ...Option) *Scanner[T] {
 	}
 }
 
+// NewScannerFrom creates a new Scanner that resumes a scan from the
+// state previously returned by Scanner.Checkpoint. The paginator must
+// implement ResumablePaginator.
+func NewScannerFrom[T any](paginator Paginator[T], state []byte, opts ...Option) (*Scanner[T], error) {
+	rp, ok := paginator.(ResumablePaginator)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement operations.ResumablePaginator", paginator)
+	}
+	if err := rp.UnmarshalState(state); err != nil {
+		return nil, fmt.Errorf("failed to restore paginator state: %w", err)
+	}
+	return NewScanner(paginator, opts...), nil
+}
+
 func (sc *Scanner[T]) first(ctx context.Context) (bool, error) {
 	var empty T
 	req, done, err := sc.paginator.Next(ctx, empty, nil)
@@ -72,15 +106,26 @@ func (sc *Scanner[T]) Scan(ctx context.Context) bool {
 			return false
 		}
 	}
+	if sc.prefetcher != nil {
+		return sc.scanPrefetched(ctx)
+	}
 	resp := <-sc.ch
 	if err := resp.err; err != nil {
 		sc.err = err
 		return false
 	}
 	sc.resp = resp
+	sc.recordPage()
 	if resp.last {
 		sc.done = true
-	} else {
+		return true
+	}
+	prefetching, err := sc.startPrefetch(ctx, resp)
+	if err != nil {
+		sc.err = err
+		return false
+	}
+	if !prefetching {
 		go sc.get(ctx, resp.nextReq)
 	}
 	return true
@@ -96,30 +141,76 @@ func (sc *Scanner[T]) HTTPResponse() *http.Response {
 	return sc.resp.httpResponse
 }
 
-// Body returns the body for the current page.
+// Body returns the body for the current page. It is nil if
+// streaming decoding is enabled via WithStreaming.
 func (sc *Scanner[T]) Body() []byte {
 	return sc.resp.body
 }
 
 func (sc *Scanner[T]) get(ctx context.Context, req *http.Request) {
-	payload, resp, body, err := sc.ep.getWithResp(ctx, req)
+	ctx, span := StartSpan(ctx, SpanScanPage)
+	res, err := sc.ep.getWithResp(ctx, req)
 	if err != nil {
-		sc.ch <- response[T]{response: payload, body: body, last: true, err: err}
+		endSpan(span, err)
+		sc.ch <- response[T]{response: res.value, body: res.body, last: true, err: err}
 		return
 	}
-	req, last, err := sc.paginator.Next(ctx, payload, resp)
+	req, last, err := sc.paginator.Next(ctx, res.value, res.resp)
+	var state []byte
+	if err == nil {
+		state, err = sc.marshalState()
+	}
+	endSpan(span, err)
 	if err != nil {
-		sc.ch <- response[T]{response: payload, body: body, last: true, err: err}
+		sc.ch <- response[T]{response: res.value, body: res.body, last: true, err: err}
 		return
 	}
 	sc.ch <- response[T]{
-		response:     payload,
-		body:         body,
+		response:     res.value,
+		body:         res.body,
+		encoding:     res.encoding,
 		last:         last,
 		err:          nil,
 		nextReq:      req,
-		httpResponse: resp,
+		state:        state,
+		httpResponse: res.resp,
+	}
+}
+
+// marshalState returns the paginator's state if it implements
+// ResumablePaginator. It must be called immediately after the paginator's
+// Next method is called for a page since Next may be called again for the
+// following page before the current page has been processed by the caller.
+func (sc *Scanner[T]) marshalState() ([]byte, error) {
+	rp, ok := sc.paginator.(ResumablePaginator)
+	if !ok {
+		return nil, nil
 	}
+	state, err := rp.MarshalState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal paginator state: %w", err)
+	}
+	return state, nil
+}
+
+// Checkpoint returns the state of the scan's paginator following the
+// current page, ie. that returned by Response, if the paginator implements
+// ResumablePaginator, and nil otherwise. Once the current page has been
+// processed, the returned state may be saved and later passed to
+// NewScannerFrom to resume the scan with the following page.
+func (sc *Scanner[T]) Checkpoint() []byte {
+	return sc.resp.state
+}
+
+func (sc *Scanner[T]) recordPage() {
+	if sc.ep.metrics != nil {
+		sc.ep.metrics.Pages(sc.ep.metricLabels, 1)
+	}
+}
+
+// Encoding returns the encoding of the body for the current page.
+func (sc *Scanner[T]) Encoding() Encoding {
+	return sc.resp.encoding
 }
 
 // Err returns the first error encountered during scanning.
@@ -6,10 +6,13 @@ package operations_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"cloudeng.io/webapi/operations"
 	"cloudeng.io/webapi/webapitestutil"
@@ -154,3 +157,156 @@ func TestScannerErrorAfterN(t *testing.T) {
 		t.Errorf("got %v, want %v", got, want)
 	}
 }
+
+type prefetchPaginator struct {
+	url   string
+	calls []int
+}
+
+func (p *prefetchPaginator) Next(_ context.Context, payload webapitestutil.Paginated, resp *http.Response) (*http.Request, bool, error) {
+	if resp == nil {
+		req, err := http.NewRequest("GET", p.url, nil)
+		return req, false, err
+	}
+	p.calls = append(p.calls, payload.Current)
+	req, err := http.NewRequest("GET", fmt.Sprintf(p.url+"?current=%v", payload.Current+1), nil)
+	return req, payload.Current == payload.Last, err
+}
+
+func (p *prefetchPaginator) Upcoming(_ context.Context, payload webapitestutil.Paginated, _ *http.Response) ([]*http.Request, error) {
+	reqs := []*http.Request{}
+	for i := payload.Current + 1; i <= payload.Last; i++ {
+		req, err := http.NewRequest("GET", fmt.Sprintf(p.url+"?current=%v", i), nil)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+func TestScannerPrefetch(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	inflight, maxInflight := 0, 0
+	last := 20
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inflight++
+		maxInflight = max(maxInflight, inflight)
+		mu.Unlock()
+		current, _ := strconv.Atoi(r.URL.Query().Get("current"))
+		// Make earlier pages slower to ensure that they complete out of order.
+		time.Sleep(time.Duration(last-current) * time.Millisecond)
+		_ = json.NewEncoder(w).Encode(webapitestutil.Paginated{Payload: current, Current: current, Last: last})
+		mu.Lock()
+		inflight--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	paginator := &prefetchPaginator{url: srv.URL}
+	scanner := operations.NewScanner[webapitestutil.Paginated](paginator, operations.WithPrefetch(4))
+	expected := 0
+	for scanner.Scan(ctx) {
+		if got, want := scanner.Response().Current, expected; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		expected++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := expected, last+1; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for i, c := range paginator.calls {
+		if got, want := c, i; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	if maxInflight < 2 || maxInflight > 4 {
+		t.Errorf("unexpected concurrency: %v", maxInflight)
+	}
+}
+
+type resumablePaginator struct {
+	url  string
+	Page int  `json:"page"`
+	Done bool `json:"done"`
+}
+
+func (p *resumablePaginator) Next(_ context.Context, payload webapitestutil.Paginated, resp *http.Response) (*http.Request, bool, error) {
+	if resp != nil {
+		if payload.Current == payload.Last {
+			p.Done = true
+			return nil, true, nil
+		}
+		p.Page = payload.Current + 1
+	} else if p.Done {
+		return nil, true, nil
+	}
+	req, err := http.NewRequest("GET", fmt.Sprintf("%v?current=%v", p.url, p.Page), nil)
+	return req, false, err
+}
+
+func (p *resumablePaginator) MarshalState() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *resumablePaginator) UnmarshalState(buf []byte) error {
+	return json.Unmarshal(buf, p)
+}
+
+func TestScannerResume(t *testing.T) {
+	ctx := context.Background()
+	srv := webapitestutil.NewServer(&webapitestutil.PaginatedHandler{Last: 10})
+	defer srv.Close()
+
+	scan := func(sc *operations.Scanner[webapitestutil.Paginated], stopAt int) ([]int, []byte) {
+		var pages []int
+		var state []byte
+		for sc.Scan(ctx) {
+			pages = append(pages, sc.Response().Current)
+			state = sc.Checkpoint()
+			if sc.Response().Current == stopAt {
+				break
+			}
+		}
+		if err := sc.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return pages, state
+	}
+
+	sc := operations.NewScanner[webapitestutil.Paginated](&resumablePaginator{url: srv.URL})
+	pages, state := scan(sc, 3)
+	if got, want := fmt.Sprint(pages), "[0 1 2 3]"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := string(state), `{"page":4,"done":false}`; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	sc, err := operations.NewScannerFrom[webapitestutil.Paginated](&resumablePaginator{url: srv.URL}, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, state = scan(sc, -1)
+	if got, want := fmt.Sprint(pages), "[4 5 6 7 8 9 10]"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Resuming a completed scan returns no pages.
+	sc, err = operations.NewScannerFrom[webapitestutil.Paginated](&resumablePaginator{url: srv.URL}, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages, _ = scan(sc, -1); len(pages) != 0 {
+		t.Errorf("unexpected pages: %v", pages)
+	}
+
+	if _, err := operations.NewScannerFrom[webapitestutil.Paginated](&paginator{url: srv.URL}, state); err == nil {
+		t.Errorf("expected an error for a paginator that is not resumable")
+	}
+}
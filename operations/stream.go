@@ -0,0 +1,194 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrResponseTooLarge is returned, wrapped in an *Error, when a response
+// body exceeds the size configured via WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// Stream represents the body of a successful response that is to be read
+// incrementally rather than being read into memory in its entirety.
+// The Stream must be closed once it has been read.
+type Stream struct {
+	io.Reader
+	// Response is the http.Response whose body is being streamed, note
+	// that its Body should not be read directly.
+	Response *http.Response
+	Encoding Encoding
+	body     io.Closer
+	store    io.Closer
+	reader   *streamReader
+	finish   func(received int64, err error)
+	once     sync.Once
+}
+
+// streamReader counts the number of bytes read and records the first
+// error, other than io.EOF, encountered whilst reading.
+type streamReader struct {
+	rd  io.Reader
+	n   int64
+	err error
+}
+
+func (sr *streamReader) Read(p []byte) (int, error) {
+	n, err := sr.rd.Read(p)
+	sr.n += int64(n)
+	if err != nil && err != io.EOF && sr.err == nil {
+		sr.err = err
+	}
+	return n, err
+}
+
+// Decoder returns a json.Decoder that reads from the stream.
+func (s *Stream) Decoder() *json.Decoder {
+	return json.NewDecoder(s.Reader)
+}
+
+// CompressedSize returns the content coding used for the response body
+// as received and the number of bytes of that body read so far, see
+// WithCompression.
+func (s *Stream) CompressedSize() (string, int64) {
+	return compressedSize(s.Response)
+}
+
+// Close closes the underlying response body and any writer obtained
+// from a BodyStore. The request's metrics and trace span are recorded
+// when the Stream is closed so that they include the time taken to read
+// the body, the number of bytes read and any error encountered whilst
+// reading it.
+func (s *Stream) Close() error {
+	err := s.body.Close()
+	if s.store != nil {
+		if serr := s.store.Close(); err == nil {
+			err = serr
+		}
+	}
+	s.once.Do(func() {
+		if s.finish == nil {
+			return
+		}
+		if s.reader.err != nil {
+			s.finish(s.reader.n, s.reader.err)
+			return
+		}
+		s.finish(s.reader.n, err)
+	})
+	return err
+}
+
+// Stream issues the supplied request, with the same rate control, backoff
+// and authorization as Get etc, and returns the response body as a Stream
+// rather than decoding it. The size limit configured via WithMaxResponseSize
+// and any BodyStore configured via WithBodyStore are applied to the Stream.
+// Non-success responses are returned as errors as for Get.
+func (ep *Endpoint[T]) Stream(ctx context.Context, req *http.Request) (*Stream, error) {
+	ctx, span := ep.startRequestSpan(ctx, req)
+	stats := requestStats{span: span}
+	start := time.Now()
+	resp, retries, err := ep.do(ctx, req, &stats)
+	if err != nil {
+		ep.annotateError(req, stats.history, err)
+		ep.recordRequest(req, result[T]{}, start, stats, err)
+		endRequestSpan(span, nil, stats, err)
+		return nil, err
+	}
+	if !ep.isSuccess(resp.StatusCode) {
+		res, err := ep.handleErrorResponse(resp, retries)
+		ep.annotateError(req, stats.history, err)
+		ep.recordRequest(req, res, start, stats, err)
+		endRequestSpan(span, resp, stats, err)
+		return nil, err
+	}
+	rd, store, err := ep.responseReader(ctx, req, resp)
+	if err != nil {
+		resp.Body.Close()
+		err = handleError(err, resp.Status, resp.StatusCode, retries)
+		ep.annotateError(req, stats.history, err)
+		ep.recordRequest(req, result[T]{resp: resp}, start, stats, err)
+		endRequestSpan(span, resp, stats, err)
+		return nil, err
+	}
+	sr := &streamReader{rd: rd}
+	return &Stream{
+		Reader:   sr,
+		Response: resp,
+		Encoding: ep.streamEncoding(resp),
+		body:     resp.Body,
+		store:    store,
+		reader:   sr,
+		finish: func(received int64, err error) {
+			ep.recordRequest(req, result[T]{resp: resp, streamed: true, received: received}, start, stats, err)
+			endRequestSpan(span, resp, stats, err)
+		},
+	}, nil
+}
+
+func (ep *Endpoint[T]) streamEncoding(resp *http.Response) Encoding {
+	if ep.autoEncoding {
+		return EncodingForResponse(resp)
+	}
+	return ep.encoding
+}
+
+// responseReader returns a reader for the body of resp that enforces
+// any size limit and which writes the body to the BodyStore, if one
+// is configured, as it is read.
+func (ep *Endpoint[T]) responseReader(ctx context.Context, req *http.Request, resp *http.Response) (io.Reader, io.WriteCloser, error) {
+	if ep.maxResponseSize > 0 && resp.ContentLength > ep.maxResponseSize {
+		return nil, nil, fmt.Errorf("%w: content length %v exceeds limit of %v bytes", ErrResponseTooLarge, resp.ContentLength, ep.maxResponseSize)
+	}
+	rd := ep.limitBody(resp.Body)
+	if ep.bodyStore == nil {
+		return rd, nil, nil
+	}
+	wr, err := ep.bodyStore.Writer(ctx, req, resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return io.TeeReader(rd, wr), wr, nil
+}
+
+func (ep *Endpoint[T]) limitBody(rd io.Reader) io.Reader {
+	if ep.maxResponseSize <= 0 {
+		return rd
+	}
+	return &limitedReader{rd: rd, remaining: ep.maxResponseSize}
+}
+
+// limitedReader is like io.LimitedReader except that it returns
+// ErrResponseTooLarge rather than io.EOF if the underlying reader
+// contains more than the allowed number of bytes.
+type limitedReader struct {
+	rd        io.Reader
+	remaining int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.remaining <= 0 {
+		var probe [1]byte
+		n, err := lr.rd.Read(probe[:])
+		if n > 0 {
+			return 0, fmt.Errorf("%w: exceeds limit", ErrResponseTooLarge)
+		}
+		return 0, err
+	}
+	if int64(len(p)) > lr.remaining {
+		p = p[:lr.remaining]
+	}
+	n, err := lr.rd.Read(p)
+	lr.remaining -= int64(n)
+	return n, err
+}
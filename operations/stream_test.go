@@ -0,0 +1,187 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+type bufferStore struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (bs *bufferStore) Writer(context.Context, *http.Request, *http.Response) (io.WriteCloser, error) {
+	return bs, nil
+}
+
+func (bs *bufferStore) Close() error {
+	bs.closed = true
+	return nil
+}
+
+func TestStreaming(t *testing.T) {
+	ctx := context.Background()
+	eg := example{"foo", 42}
+	srv := webapitestutil.NewServer(webapitestutil.NewEchoHandler(&eg))
+	defer srv.Close()
+	data, _ := json.Marshal(eg)
+
+	store := &bufferStore{}
+	client := operations.NewEndpoint[example](
+		operations.WithStreaming(operations.JSONStreamUnmarshal, operations.JSONEncoding),
+		operations.WithBodyStore(store))
+	got, body, _, err := client.Get(ctx, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := got, eg; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if body != nil {
+		t.Errorf("unexpected body: %s", body)
+	}
+	if got, want := store.Bytes(), data; !bytes.Equal(got, want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if !store.closed {
+		t.Errorf("store was not closed")
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	stream, err := operations.NewEndpoint[example]().Stream(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded example
+	if err := stream.Decoder().Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := decoded, eg; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	ctx := context.Background()
+	large := strings.Repeat("x", 1024)
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("chunked") != "" {
+			w.(http.Flusher).Flush()
+		}
+		_ = json.NewEncoder(w).Encode(large)
+	}))
+	defer srv.Close()
+
+	for _, opt := range []operations.Option{
+		nil,
+		operations.WithStreaming(operations.JSONStreamUnmarshal, operations.JSONEncoding),
+	} {
+		opts := []operations.Option{operations.WithMaxResponseSize(100)}
+		if opt != nil {
+			opts = append(opts, opt)
+		}
+		client := operations.NewEndpoint[string](opts...)
+		for _, url := range []string{srv.URL, srv.URL + "?chunked=1"} {
+			_, _, _, err := client.Get(ctx, url)
+			if !errors.Is(err, operations.ErrResponseTooLarge) {
+				t.Errorf("%v: unexpected or missing error: %v", url, err)
+			}
+		}
+		client = operations.NewEndpoint[string](operations.WithMaxResponseSize(2048))
+		got, _, _, err := client.Get(ctx, srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := got, large; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestStreamMetricsAndTracing(t *testing.T) {
+	large := strings.Repeat("x", 1024)
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.(http.Flusher).Flush()
+		_, _ = io.WriteString(w, large)
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		limit   int64
+		wantErr error
+	}{
+		{0, nil},
+		{100, operations.ErrResponseTooLarge},
+	} {
+		tracer := operations.NewMemoryTracer()
+		ctx := operations.ContextWithTracer(context.Background(), tracer)
+		reg := operations.NewMetricsRegistry()
+		labels := operations.MetricLabels{Client: "test", Endpoint: "stream"}
+		ep := operations.NewEndpoint[example](
+			operations.WithMetrics(reg, labels),
+			operations.WithMaxResponseSize(tc.limit))
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		stream, err := ep.Stream(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Nothing is recorded until the stream is closed.
+		if got, want := len(spansNamed(tracer.Spans(), operations.SpanRequest)), 0; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if got, want := reg.Value("webapi_requests_total", labels), 0.0; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		delay := 20 * time.Millisecond
+		time.Sleep(delay)
+		_, err = io.ReadAll(stream)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("unexpected or missing error: %v", err)
+		}
+		if err := stream.Close(); err != nil {
+			t.Fatal(err)
+		}
+		stream.Close() // A second close must not record the request twice.
+
+		spans := spansNamed(tracer.Spans(), operations.SpanRequest)
+		if got, want := len(spans), 1; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got := spans[0].End.Sub(spans[0].Start); got < delay {
+			t.Errorf("got %v, want >= %v", got, delay)
+		}
+		if got, want := len(spans[0].Errors) > 0, tc.wantErr != nil; got != want {
+			t.Errorf("got %v, want %v: %v", got, want, spans[0].Errors)
+		}
+		wantBytes, wantErrors := float64(len(large)), 0.0
+		if tc.wantErr != nil {
+			wantBytes, wantErrors = float64(tc.limit), 1
+		}
+		for name, want := range map[string]float64{
+			"webapi_requests_total":                1,
+			"webapi_request_errors_total":          wantErrors,
+			"webapi_response_bytes_received_total": wantBytes,
+		} {
+			if got := reg.Value(name, labels); got != want {
+				t.Errorf("%v: got %v, want %v", name, got, want)
+			}
+		}
+	}
+}
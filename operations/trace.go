@@ -0,0 +1,280 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Attribute represents a key/value pair attached to a Span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr returns a new Attribute.
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span represents a single traced operation. Its methods mirror those
+// of an OpenTelemetry span so that an OpenTelemetry tracer can be used by
+// way of a thin adapter.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer creates new Spans. The returned context must carry the new
+// span so that spans created using it are its children.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// The names of the spans created by this package.
+const (
+	SpanRequest   = "webapi.request"
+	SpanAuth      = "webapi.auth"
+	SpanBackoff   = "webapi.backoff"
+	SpanScanPage  = "webapi.scan.page"
+	SpanFetchPage = "webapi.crawl.fetch"
+	SpanFetchItem = "webapi.fetch.item"
+)
+
+type tracerKey struct{}
+
+// ContextWithTracer returns a context that carries the supplied Tracer,
+// which is then used to trace all of the operations performed using
+// that context, by Endpoints, Scanners, Crawlers and any Fetchers or
+// Auth implementations that use StartSpan.
+func ContextWithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// TracerFromContext returns the Tracer carried by ctx, or NoopTracer
+// if there is none.
+func TracerFromContext(ctx context.Context) Tracer {
+	if t, ok := ctx.Value(tracerKey{}).(Tracer); ok {
+		return t
+	}
+	return NoopTracer{}
+}
+
+// StartSpan starts a new span using the Tracer carried by ctx.
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return TracerFromContext(ctx).Start(ctx, name, attrs...)
+}
+
+// endSpan records err, if non-nil, and ends the span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// NoopTracer is a Tracer that does nothing.
+type NoopTracer struct{}
+
+// Start implements Tracer.
+func (NoopTracer) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// RecordedSpan represents a span recorded by a MemoryTracer.
+type RecordedSpan struct {
+	Name       string
+	ID         uint64
+	ParentID   uint64 // zero for root spans.
+	Start, End time.Time
+	Attributes map[string]any
+	Errors     []error
+}
+
+// MemoryTracer is a Tracer that records all spans in memory, it is
+// intended for use in tests.
+type MemoryTracer struct {
+	mu     sync.Mutex
+	nextID atomic.Uint64
+	spans  []RecordedSpan
+}
+
+// NewMemoryTracer returns a new MemoryTracer.
+func NewMemoryTracer() *MemoryTracer {
+	return &MemoryTracer{}
+}
+
+type memorySpanKey struct{}
+
+// Start implements Tracer.
+func (mt *MemoryTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	span := &memorySpan{
+		tracer: mt,
+		rec: RecordedSpan{
+			Name:       name,
+			ID:         mt.nextID.Add(1),
+			Start:      time.Now(),
+			Attributes: map[string]any{},
+		},
+	}
+	if parent, ok := ctx.Value(memorySpanKey{}).(*memorySpan); ok {
+		span.rec.ParentID = parent.rec.ID
+	}
+	span.SetAttributes(attrs...)
+	return context.WithValue(ctx, memorySpanKey{}, span), span
+}
+
+// Spans returns all of the spans that have ended, in the order in which
+// they ended.
+func (mt *MemoryTracer) Spans() []RecordedSpan {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	return append([]RecordedSpan(nil), mt.spans...)
+}
+
+type memorySpan struct {
+	tracer *MemoryTracer
+	mu     sync.Mutex
+	rec    RecordedSpan
+	ended  bool
+}
+
+func (s *memorySpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range attrs {
+		s.rec.Attributes[a.Key] = a.Value
+	}
+}
+
+func (s *memorySpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rec.Errors = append(s.rec.Errors, err)
+}
+
+func (s *memorySpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.rec.End = time.Now()
+	rec := s.rec
+	rec.Attributes = make(map[string]any, len(s.rec.Attributes))
+	for k, v := range s.rec.Attributes {
+		rec.Attributes[k] = v
+	}
+	s.mu.Unlock()
+	s.tracer.mu.Lock()
+	s.tracer.spans = append(s.tracer.spans, rec)
+	s.tracer.mu.Unlock()
+}
+
+// WithHTTPTrace specifies that net/http/httptrace is to be used to record
+// the time taken for DNS lookups, connection establishment, the TLS
+// handshake and the arrival of the first response byte for each request.
+// These are recorded, in milliseconds, as attributes of the request's span.
+func WithHTTPTrace() Option {
+	return func(o *options) {
+		o.httpTrace = true
+	}
+}
+
+// WithRedactions specifies URL query parameters, in addition to those in
+// DefaultQueryRedactions, whose values are to be redacted from the URLs
+// logged by an Endpoint, the url.full attribute of request spans and the
+// URL recorded in an Error.
+func WithRedactions(params ...string) Option {
+	return func(o *options) {
+		o.redactions = append(o.redactions, params...)
+	}
+}
+
+// withHTTPTrace returns a copy of req whose context carries an
+// httptrace.ClientTrace that records timing attributes on span.
+func withHTTPTrace(req *http.Request, span Span) *http.Request {
+	var mu sync.Mutex
+	var dnsStart, connectStart, tlsStart time.Time
+	start := time.Now()
+	ms := func(since time.Time) float64 {
+		return float64(time.Since(since)) / float64(time.Millisecond)
+	}
+	set := func(key string, since time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !since.IsZero() {
+			span.SetAttributes(Attr(key, ms(since)))
+		}
+	}
+	ct := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			mu.Lock()
+			dnsStart = time.Now()
+			mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) { set("http.trace.dns_ms", dnsStart) },
+		ConnectStart: func(string, string) {
+			mu.Lock()
+			connectStart = time.Now()
+			mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) { set("http.trace.connect_ms", connectStart) },
+		TLSHandshakeStart: func() {
+			mu.Lock()
+			tlsStart = time.Now()
+			mu.Unlock()
+		},
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set("http.trace.tls_ms", tlsStart) },
+		GotFirstResponseByte: func() { set("http.trace.first_byte_ms", start) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			span.SetAttributes(Attr("http.trace.conn_reused", info.Reused))
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), ct))
+}
+
+func (ep *Endpoint[T]) startRequestSpan(ctx context.Context, req *http.Request) (context.Context, Span) {
+	method := req.Method
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	return StartSpan(ctx, SpanRequest,
+		Attr("http.request.method", method),
+		Attr("url.full", ep.redactedURL(req.URL)))
+}
+
+// redactedURL returns u with any userinfo password and the values of the
+// query parameters in DefaultQueryRedactions and those specified via
+// WithRedactions redacted.
+func (ep *Endpoint[T]) redactedURL(u *url.URL) string {
+	return redactURL(u, redactionSet(DefaultQueryRedactions, ep.redactions))
+}
+
+func endRequestSpan(span Span, resp *http.Response, stats requestStats, err error) {
+	span.SetAttributes(Attr("webapi.attempts", stats.attempts))
+	if stats.hedged {
+		span.SetAttributes(Attr("webapi.hedge_won", stats.hedgeWon))
+	}
+	if resp != nil {
+		span.SetAttributes(Attr("http.response.status_code", resp.StatusCode))
+	}
+	endSpan(span, err)
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package operations_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloudeng.io/file/content"
+	"cloudeng.io/net/ratecontrol"
+	"cloudeng.io/webapi/operations"
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func spansNamed(spans []operations.RecordedSpan, name string) []operations.RecordedSpan {
+	var r []operations.RecordedSpan
+	for _, s := range spans {
+		if s.Name == name {
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+func TestTracing(t *testing.T) {
+	tracer := operations.NewMemoryTracer()
+	ctx := operations.ContextWithTracer(context.Background(), tracer)
+	var mu sync.Mutex
+	calls := 0
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(example{"foo", 42})
+	}))
+	defer srv.Close()
+
+	rc := ratecontrol.New(ratecontrol.WithExponentialBackoff(time.Millisecond, 5))
+	ep := operations.NewEndpoint[example](
+		operations.WithRateController(rc, http.StatusTooManyRequests),
+		operations.WithAuth(&authToken{"token"}),
+		operations.WithHTTPTrace())
+	if _, _, _, err := ep.Get(ctx, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := tracer.Spans()
+	reqs := spansNamed(spans, operations.SpanRequest)
+	if got, want := len(reqs), 1; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	req := reqs[0]
+	if got, want := req.ParentID, uint64(0); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for k, v := range map[string]any{
+		"http.request.method":       "GET",
+		"url.full":                  srv.URL,
+		"http.response.status_code": http.StatusOK,
+		"webapi.attempts":           2,
+	} {
+		if got, want := req.Attributes[k], v; got != want {
+			t.Errorf("%v: got %v, want %v", k, got, want)
+		}
+	}
+	for _, k := range []string{"http.trace.connect_ms", "http.trace.first_byte_ms", "http.trace.conn_reused"} {
+		if _, ok := req.Attributes[k]; !ok {
+			t.Errorf("missing attribute %v: %v", k, req.Attributes)
+		}
+	}
+	for _, name := range []string{operations.SpanAuth, operations.SpanBackoff} {
+		children := spansNamed(spans, name)
+		if got, want := len(children), 1; got != want {
+			t.Errorf("%v: got %v, want %v", name, got, want)
+			continue
+		}
+		if got, want := children[0].ParentID, req.ID; got != want {
+			t.Errorf("%v: got %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestTracingRedaction(t *testing.T) {
+	tracer := operations.NewMemoryTracer()
+	ctx := operations.ContextWithTracer(context.Background(), tracer)
+	srv := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(example{"foo", 42})
+	}))
+	defer srv.Close()
+
+	ep := operations.NewEndpoint[example](operations.WithRedactions("sig"))
+	if _, _, _, err := ep.Get(ctx, srv.URL+"?api_key=secret1&sig=secret2&q=visible"); err != nil {
+		t.Fatal(err)
+	}
+	reqs := spansNamed(tracer.Spans(), operations.SpanRequest)
+	if got, want := len(reqs), 1; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range reqs[0].Attributes {
+		if s := fmt.Sprint(v); strings.Contains(s, "secret") {
+			t.Errorf("%v: secret not redacted: %v", k, s)
+		}
+	}
+	if got, want := reqs[0].Attributes["url.full"], srv.URL+"?api_key=REDACTED&q=visible&sig=REDACTED"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCrawlTracing(t *testing.T) {
+	tracer := operations.NewMemoryTracer()
+	ctx := operations.ContextWithTracer(context.Background(), tracer)
+	mux := http.NewServeMux()
+	mux.Handle("/list", &webapitestutil.PaginatedHandler{
+		Last: 3,
+	})
+	mux.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Object{ID: r.URL.Query().Get("id")})
+	})
+	srv := webapitestutil.NewServer(mux)
+	defer srv.Close()
+
+	paginator := &paginator{url: srv.URL + "/list"}
+	scanner := operations.NewScanner[webapitestutil.Paginated](paginator)
+	fetcher := &fetcher{url: srv.URL, ep: operations.NewEndpoint[Object]()}
+	cr := operations.NewCrawler[webapitestutil.Paginated, Object](scanner, fetcher)
+	err := operations.RunCrawl(ctx, cr, func(context.Context, []content.Object[Object, operations.Response]) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := tracer.Spans()
+	parents := map[uint64]string{}
+	for _, s := range spans {
+		parents[s.ID] = s.Name
+	}
+	counts := map[string]int{}
+	for _, s := range spans {
+		if s.Name != operations.SpanRequest {
+			continue
+		}
+		counts[parents[s.ParentID]]++
+	}
+	if got, want := counts[operations.SpanScanPage], 4; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := counts[operations.SpanFetchPage], 4; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package webapitestutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Redacted is the value used to replace scrubbed secrets.
+const Redacted = "REDACTED"
+
+// RecordedBody represents a request or response body, bodies that are
+// not valid UTF-8 are stored base64 encoded.
+type RecordedBody struct {
+	Data   string `json:"data,omitempty"`
+	Base64 bool   `json:"base64,omitempty"`
+}
+
+func newRecordedBody(data []byte) RecordedBody {
+	if utf8.Valid(data) {
+		return RecordedBody{Data: string(data)}
+	}
+	return RecordedBody{Data: base64.StdEncoding.EncodeToString(data), Base64: true}
+}
+
+// Bytes returns the decoded body.
+func (rb RecordedBody) Bytes() []byte {
+	if !rb.Base64 {
+		return []byte(rb.Data)
+	}
+	data, err := base64.StdEncoding.DecodeString(rb.Data)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// RecordedRequest represents a recorded http.Request.
+type RecordedRequest struct {
+	Method string       `json:"method"`
+	URL    string       `json:"url"`
+	Header http.Header  `json:"header,omitempty"`
+	Body   RecordedBody `json:"body"`
+}
+
+// RecordedResponse represents a recorded http.Response.
+type RecordedResponse struct {
+	StatusCode int          `json:"status_code"`
+	Header     http.Header  `json:"header,omitempty"`
+	Body       RecordedBody `json:"body"`
+}
+
+// Interaction represents a single recorded request/response exchange.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Cassette represents a sequence of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette reads a cassette from the specified file.
+func LoadCassette(filename string) (*Cassette, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(buf, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %v: %w", filename, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to the specified file.
+func (c *Cassette) Save(filename string) error {
+	buf, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf, 0600)
+}
+
+// CassetteOption represents an option for recording and replaying
+// cassettes.
+type CassetteOption func(o *cassetteOptions)
+
+type cassetteOptions struct {
+	headers map[string]bool
+	params  map[string]bool
+	scrub   []func(*Interaction)
+}
+
+// DefaultScrubbedHeaders are the headers whose values are scrubbed by default.
+var DefaultScrubbedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"Api-Key",
+	"X-Auth-Token",
+	"X-Amz-Security-Token",
+}
+
+// DefaultScrubbedParams are the URL query parameters whose values are
+// scrubbed by default.
+var DefaultScrubbedParams = []string{
+	"api_key",
+	"apikey",
+	"access_token",
+	"refresh_token",
+	"client_secret",
+	"token",
+	"password",
+}
+
+// WithScrubbedHeaders specifies additional headers whose values are to be
+// replaced by Redacted.
+func WithScrubbedHeaders(names ...string) CassetteOption {
+	return func(o *cassetteOptions) {
+		for _, n := range names {
+			o.headers[http.CanonicalHeaderKey(n)] = true
+		}
+	}
+}
+
+// WithScrubbedParams specifies additional URL query parameters whose values
+// are to be replaced by Redacted.
+func WithScrubbedParams(names ...string) CassetteOption {
+	return func(o *cassetteOptions) {
+		for _, n := range names {
+			o.params[strings.ToLower(n)] = true
+		}
+	}
+}
+
+// WithScrubber specifies a function that is called to scrub any other
+// secrets, eg. from request or response bodies, from each interaction
+// before it is recorded. Note that the scrubber is also applied to
+// requests received by a replay server before they are matched against
+// the recorded interactions.
+func WithScrubber(fn func(*Interaction)) CassetteOption {
+	return func(o *cassetteOptions) {
+		o.scrub = append(o.scrub, fn)
+	}
+}
+
+func newCassetteOptions(opts []CassetteOption) cassetteOptions {
+	o := cassetteOptions{
+		headers: map[string]bool{},
+		params:  map[string]bool{},
+	}
+	WithScrubbedHeaders(DefaultScrubbedHeaders...)(&o)
+	WithScrubbedParams(DefaultScrubbedParams...)(&o)
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
+func (o cassetteOptions) scrubHeader(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	h = h.Clone()
+	for k := range h {
+		if o.headers[http.CanonicalHeaderKey(k)] {
+			h[k] = []string{Redacted}
+		}
+	}
+	return h
+}
+
+func (o cassetteOptions) scrubURL(u *url.URL) string {
+	cpy := *u
+	q := cpy.Query()
+	changed := false
+	for k := range q {
+		if o.params[strings.ToLower(k)] {
+			q[k] = []string{Redacted}
+			changed = true
+		}
+	}
+	if changed {
+		cpy.RawQuery = q.Encode()
+	}
+	return cpy.String()
+}
+
+func (o cassetteOptions) scrubInteraction(in *Interaction) {
+	for _, fn := range o.scrub {
+		fn(in)
+	}
+}
+
+// Recorder is an http.RoundTripper that records all of the exchanges
+// made via it, with secrets scrubbed, to a Cassette.
+type Recorder struct {
+	transport http.RoundTripper
+	opts      cassetteOptions
+	mu        sync.Mutex
+	cassette  Cassette
+}
+
+// NewRecorder returns a new Recorder that uses the supplied transport,
+// or http.DefaultTransport if nil, to make requests.
+func NewRecorder(transport http.RoundTripper, opts ...CassetteOption) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{
+		transport: transport,
+		opts:      newCassetteOptions(opts),
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	in := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    r.opts.scrubURL(req.URL),
+			Header: r.opts.scrubHeader(req.Header),
+			Body:   newRecordedBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.opts.scrubHeader(resp.Header),
+			Body:       newRecordedBody(respBody),
+		},
+	}
+	r.opts.scrubInteraction(&in)
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the interactions recorded so far to the specified file.
+func (r *Recorder) Save(filename string) error {
+	return r.Cassette().Save(filename)
+}
+
+// NewReplayHandler returns an http.Handler that serves the responses
+// recorded in the cassette. Requests are matched against the recorded
+// interactions on their method, path, query parameters and body (JSON
+// bodies are compared semantically), after applying the same scrubbing
+// as used when recording. Identical requests are served the matching
+// interactions in the order that they were recorded, with the last
+// matching interaction being repeated once all have been served. Requests
+// that do not match any interaction receive a http.StatusNotImplemented
+// response.
+func NewReplayHandler(c *Cassette, opts ...CassetteOption) http.Handler {
+	return &replayHandler{
+		cassette: c,
+		opts:     newCassetteOptions(opts),
+		used:     make([]bool, len(c.Interactions)),
+	}
+}
+
+// NewReplayServer returns a new httptest.Server that serves the
+// responses recorded in the cassette, see NewReplayHandler.
+func NewReplayServer(c *Cassette, opts ...CassetteOption) *httptest.Server {
+	return NewServer(NewReplayHandler(c, opts...))
+}
+
+type replayHandler struct {
+	cassette *Cassette
+	opts     cassetteOptions
+	mu       sync.Mutex
+	used     []bool
+}
+
+func (rh *replayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	in := Interaction{
+		Request: RecordedRequest{
+			Method: r.Method,
+			URL:    rh.opts.scrubURL(r.URL),
+			Header: rh.opts.scrubHeader(r.Header),
+			Body:   newRecordedBody(body),
+		},
+	}
+	rh.opts.scrubInteraction(&in)
+	resp, ok := rh.match(in.Request)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no recorded interaction for %v %v", r.Method, r.URL), http.StatusNotImplemented)
+		return
+	}
+	// Content-Encoding is retained since the recorded body is exactly as
+	// received: it is only decoded if the transport used for recording
+	// decoded it transparently, in which case the transport also removed
+	// the header.
+	for k, v := range resp.Header {
+		switch http.CanonicalHeaderKey(k) {
+		case "Content-Length", "Transfer-Encoding":
+			// Recomputed when the recorded body is written.
+			continue
+		}
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(resp.Body.Bytes())
+}
+
+func (rh *replayHandler) match(req RecordedRequest) (RecordedResponse, bool) {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	last := -1
+	for i, in := range rh.cassette.Interactions {
+		if !requestsMatch(in.Request, req) {
+			continue
+		}
+		if !rh.used[i] {
+			rh.used[i] = true
+			return in.Response, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return RecordedResponse{}, false
+	}
+	return rh.cassette.Interactions[last].Response, true
+}
+
+func requestsMatch(recorded, req RecordedRequest) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	ru, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return false
+	}
+	if ru.Path != u.Path || ru.Query().Encode() != u.Query().Encode() {
+		return false
+	}
+	return bodiesMatch(recorded.Body.Bytes(), req.Body.Bytes())
+}
+
+func bodiesMatch(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return false
+	}
+	an, _ := json.Marshal(av)
+	bn, _ := json.Marshal(bv)
+	return bytes.Equal(an, bn)
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 cloudeng llc. All rights reserved.
+// Use of this source code is governed by the Apache-2.0
+// license that can be found in the LICENSE file.
+
+package webapitestutil_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cloudeng.io/webapi/webapitestutil"
+)
+
+func get(t *testing.T, client *http.Client, method, url, body string, hdr map[string]string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range hdr {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(buf)
+}
+
+func TestCassette(t *testing.T) {
+	calls := 0
+	live := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		_, _ = io.WriteString(w, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("page")+" "+string(body)+" "+strings.Repeat("!", calls))
+	}))
+	defer live.Close()
+
+	rec := webapitestutil.NewRecorder(nil, webapitestutil.WithScrubbedHeaders("X-Secret"))
+	client := &http.Client{Transport: rec}
+	auth := map[string]string{"Authorization": "Bearer live-token", "X-Secret": "s"}
+	recorded := []string{}
+	for _, tc := range []struct{ method, url, body string }{
+		{"GET", live.URL + "/list?page=1&api_key=live-key", ""},
+		{"GET", live.URL + "/list?page=1&api_key=live-key", ""},
+		{"POST", live.URL + "/item", `{"a":1,"b":2}`},
+	} {
+		_, body := get(t, client, tc.method, tc.url, tc.body, auth)
+		recorded = append(recorded, body)
+	}
+
+	filename := filepath.Join(t.TempDir(), "cassette.json")
+	if err := rec.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	cassette, err := webapitestutil.LoadCassette(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(cassette.Interactions), 3; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	first := cassette.Interactions[0]
+	for _, secret := range []string{"live-key", "live-token"} {
+		if strings.Contains(first.Request.URL, secret) || strings.Contains(first.Request.Header.Get("Authorization"), secret) {
+			t.Errorf("secret %q was not scrubbed: %v", secret, first.Request)
+		}
+	}
+	if got, want := first.Request.Header.Get("X-Secret"), webapitestutil.Redacted; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := first.Response.Header.Get("Set-Cookie"), webapitestutil.Redacted; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	replay := webapitestutil.NewReplayServer(cassette)
+	defer replay.Close()
+	// Note that secrets differ from those used when recording and that
+	// the JSON body is formatted differently.
+	auth = map[string]string{"Authorization": "Bearer other-token"}
+	for i, tc := range []struct{ method, url, body string }{
+		{"GET", replay.URL + "/list?api_key=other&page=1", ""},
+		{"GET", replay.URL + "/list?api_key=other&page=1", ""},
+		{"POST", replay.URL + "/item", `{"b": 2, "a": 1}`},
+	} {
+		code, body := get(t, http.DefaultClient, tc.method, tc.url, tc.body, auth)
+		if got, want := code, http.StatusOK; got != want {
+			t.Errorf("%v: got %v, want %v", i, got, want)
+		}
+		if got, want := body, recorded[i]; got != want {
+			t.Errorf("%v: got %v, want %v", i, got, want)
+		}
+	}
+	// Repeated requests are served the last matching response.
+	if _, body := get(t, http.DefaultClient, "GET", replay.URL+"/list?page=1&api_key=x", "", nil); body != recorded[1] {
+		t.Errorf("got %v, want %v", body, recorded[1])
+	}
+	if code, _ := get(t, http.DefaultClient, "GET", replay.URL+"/list?page=2", "", nil); code != http.StatusNotImplemented {
+		t.Errorf("got %v, want %v", code, http.StatusNotImplemented)
+	}
+	if got, want := calls, 3; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCassetteCompression(t *testing.T) {
+	const text = "compressed response body"
+	live := webapitestutil.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = io.WriteString(gz, text)
+		gz.Close()
+	}))
+	defer live.Close()
+
+	gunzip := func(body string) string {
+		rd, err := gzip.NewReader(strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := io.ReadAll(rd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf)
+	}
+
+	explicit := map[string]string{"Accept-Encoding": "gzip"}
+	for i, hdr := range []map[string]string{
+		// The transport decodes the response transparently and hence
+		// the recorded body is not compressed.
+		nil,
+		// The client requests compression itself and hence the recorded
+		// body is compressed.
+		explicit,
+	} {
+		rec := webapitestutil.NewRecorder(nil)
+		if _, body := get(t, &http.Client{Transport: rec}, "GET", live.URL+"/data", "", hdr); hdr == nil && body != text {
+			t.Errorf("%v: got %v, want %v", i, body, text)
+		}
+		replay := webapitestutil.NewReplayServer(rec.Cassette())
+		// Replay to a client that relies on transparent decoding.
+		if _, body := get(t, http.DefaultClient, "GET", replay.URL+"/data", "", nil); body != text {
+			t.Errorf("%v: got %q, want %q", i, body, text)
+		}
+		// Replay to a client that decodes the response itself.
+		if hdr != nil {
+			if _, body := get(t, http.DefaultClient, "GET", replay.URL+"/data", "", explicit); gunzip(body) != text {
+				t.Errorf("%v: got %q, want %q", i, gunzip(body), text)
+			}
+		}
+		replay.Close()
+	}
+}